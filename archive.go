@@ -0,0 +1,225 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// archiveDir is the subdirectory of the ADR directory that archived ADRs are
+// moved to. The index only lists ADRs in the ADR directory itself.
+const archiveDir = "archive"
+
+var relationLinkPattern = regexp.MustCompile(`\]\(([^)\s]+\.md)\)|'([^'\s]+\.md)'`)
+
+// archiveMove is one ADR moving to the archive, as paths relative to the ADR
+// directory.
+type archiveMove struct {
+	From string
+	To   string
+}
+
+// archiveEdit is the new content of an ADR whose Relations links change.
+type archiveEdit struct {
+	File    string
+	Content string
+}
+
+// listADRFiles returns the ADR files in dir relative to the ADR directory,
+// e.g. "adr-001-x.md" or "archive/adr-001-x.md". A missing dir is empty.
+func listADRFiles(dir string) ([]string, error) {
+	files, err := readDirWithRetry(filepath.Join(adrDir, dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		if !file.IsDir() && isADRFile(file.Name()) {
+			names = append(names, path.Join(dir, file.Name()))
+		}
+	}
+	return names, nil
+}
+
+// rewriteRelationLinks repoints the Relations links of an ADR that lives (or
+// will live) in newDir, given the files being moved. Links are resolved from
+// oldDir; only links to known ADR files are touched.
+func rewriteRelationLinks(content, oldDir, newDir string, known map[string]bool, moved map[string]string) string {
+	lines := strings.Split(content, "\n")
+	forEachRelationLine(content, func(lineNumber int, _ string) {
+		i := lineNumber - 1
+		lines[i] = relationLinkPattern.ReplaceAllStringFunc(lines[i], func(match string) string {
+			groups := relationLinkPattern.FindStringSubmatch(match)
+			target := groups[1] + groups[2]
+
+			resolved := path.Clean(path.Join(oldDir, target))
+			if !known[resolved] {
+				return match
+			}
+			if to, ok := moved[resolved]; ok {
+				resolved = to
+			}
+
+			link := resolved
+			if newDir != "" {
+				if rest, ok := strings.CutPrefix(resolved, newDir+"/"); ok {
+					link = rest
+				} else {
+					link = "../" + resolved
+				}
+			}
+			return strings.Replace(match, target, link, 1)
+		})
+	})
+	return strings.Join(lines, "\n")
+}
+
+// planArchive works out which ADRs move to the archive and which ADRs need
+// their Relations links rewritten as a result.
+func planArchive(wanted []string) ([]archiveMove, []archiveEdit, error) {
+	active, err := listADRFiles("")
+	if err != nil {
+		return nil, nil, err
+	}
+	archived, err := listADRFiles(archiveDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	known := make(map[string]bool)
+	for _, name := range append(append([]string{}, active...), archived...) {
+		known[name] = true
+	}
+
+	contents := make(map[string]string)
+	moved := make(map[string]string)
+	var moves []archiveMove
+	for _, name := range append(append([]string{}, active...), archived...) {
+		content, err := readFileWithRetry(filepath.Join(adrDir, name))
+		if err != nil {
+			return nil, nil, err
+		}
+		contents[name] = string(content)
+
+		if path.Dir(name) != "." {
+			continue
+		}
+		status := getCurrentStatus(string(content))
+		for _, s := range wanted {
+			if strings.EqualFold(strings.TrimSpace(s), status) {
+				to := path.Join(archiveDir, name)
+				if known[to] {
+					return nil, nil, fmt.Errorf("cannot archive %s: %s already exists", name, to)
+				}
+				moved[name] = to
+				moves = append(moves, archiveMove{From: name, To: to})
+				break
+			}
+		}
+	}
+
+	var edits []archiveEdit
+	for name, content := range contents {
+		oldDir, newName := path.Dir(name), name
+		if to, ok := moved[name]; ok {
+			newName = to
+		}
+		newDir := path.Dir(newName)
+		if oldDir == "." {
+			oldDir = ""
+		}
+		if newDir == "." {
+			newDir = ""
+		}
+
+		updated := rewriteRelationLinks(content, oldDir, newDir, known, moved)
+		if updated != content || newName != name {
+			edits = append(edits, archiveEdit{File: newName, Content: updated})
+		}
+	}
+	sort.Slice(moves, func(i, j int) bool { return moves[i].From < moves[j].From })
+	sort.Slice(edits, func(i, j int) bool { return edits[i].File < edits[j].File })
+	return moves, edits, nil
+}
+
+func applyArchive(moves []archiveMove, edits []archiveEdit) error {
+	if len(moves) > 0 {
+		if err := ensureDir(filepath.Join(adrDir, archiveDir)); err != nil {
+			return err
+		}
+	}
+	for _, edit := range edits {
+		target := filepath.Join(adrDir, filepath.FromSlash(edit.File))
+		if err := withRetry(func() error { return writeFile(target, edit.Content) }); err != nil {
+			return err
+		}
+	}
+	companionMoves := make(map[string]string)
+	for _, move := range moves {
+		source := filepath.Join(adrDir, filepath.FromSlash(move.From))
+		if err := withRetry(func() error { return fsys.Remove(source) }); err != nil {
+			return err
+		}
+		debugf("removed %s", source)
+		companionMoves[source] = filepath.Join(adrDir, filepath.FromSlash(move.To))
+	}
+	return moveCompanions(companionMoves)
+}
+
+func runArchiveCommand(args []string) error {
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	registerDirFlags(fs)
+	status := fs.String("status", "", "Archive ADRs with these statuses (comma-separated, e.g., Superseded,Deprecated)")
+	dryRun := fs.Bool("dry-run", false, "Print the changes without applying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if *status == "" {
+		return fmt.Errorf("--status is required")
+	}
+	wanted := strings.Split(*status, ",")
+	for _, s := range wanted {
+		if _, ok := normalizeStatus(strings.TrimSpace(s)); !ok {
+			return fmt.Errorf("invalid --status %q (supported: %s)", s, strings.Join(statuses, ", "))
+		}
+	}
+
+	moves, edits, err := planArchive(wanted)
+	if err != nil {
+		return err
+	}
+	if len(moves) == 0 {
+		fmt.Println("No ADRs to archive")
+		return nil
+	}
+
+	movedTo := make(map[string]bool)
+	for _, move := range moves {
+		fmt.Printf("%s -> %s\n", move.From, move.To)
+		movedTo[move.To] = true
+	}
+	for _, edit := range edits {
+		if !movedTo[edit.File] {
+			fmt.Printf("%s: updated relation links\n", edit.File)
+		}
+	}
+
+	if *dryRun {
+		fmt.Println("Dry run: no files were changed")
+		return nil
+	}
+	if err := applyArchive(moves, edits); err != nil {
+		return err
+	}
+	return withRetry(updateIndex)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"adr-001-use-rabbitmq.md": "# ADR 001: Use RabbitMQ\n\n**Status**: Superseded  \n\n## Relations\n\n- Replaced by: [ADR 003](adr-003-use-kafka.md)\n- Related to: [ADR 002](adr-002-use-json.md)\n",
+		"adr-002-use-json.md":     "# ADR 002: Use JSON\n\n**Status**: Superseded  \n\n## Relations\n\n- Related to: [ADR 001](adr-001-use-rabbitmq.md)\n",
+		"adr-003-use-kafka.md":    "# ADR 003: Use Kafka\n\n**Status**: Accepted  \n\nSee [the old one](adr-001-use-rabbitmq.md).\n\n## Relations\n\n- Replaces ADR: 'adr-001-use-rabbitmq.md'\n- Related to: [docs](https://example.com/guide.md)\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	moves, edits, err := planArchive([]string{"superseded"})
+	if err != nil {
+		t.Fatalf("planArchive() failed: %v", err)
+	}
+	if len(moves) != 2 {
+		t.Fatalf("planArchive() moves = %+v, want ADRs 001 and 002", moves)
+	}
+	if err := applyArchive(moves, edits); err != nil {
+		t.Fatalf("applyArchive() failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"archive/adr-001-use-rabbitmq.md": "- Replaced by: [ADR 003](../adr-003-use-kafka.md)\n- Related to: [ADR 002](adr-002-use-json.md)\n",
+		"archive/adr-002-use-json.md":     "- Related to: [ADR 001](adr-001-use-rabbitmq.md)\n",
+		"adr-003-use-kafka.md":            "See [the old one](adr-001-use-rabbitmq.md).\n\n## Relations\n\n- Replaces ADR: 'archive/adr-001-use-rabbitmq.md'\n- Related to: [docs](https://example.com/guide.md)\n",
+	}
+	for file, suffix := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(file)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if !strings.HasSuffix(string(content), suffix) {
+			t.Errorf("%s = %q, want suffix %q", file, content, suffix)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "adr-001-use-rabbitmq.md")); !os.IsNotExist(err) {
+		t.Error("Archived ADR was left in the ADR directory")
+	}
+
+	// Archived numbers are not handed out again
+	if result := getNextADRNumber(); result != "004" {
+		t.Errorf("getNextADRNumber() = %q, want %q", result, "004")
+	}
+	issues, err := lintADRs()
+	if err != nil {
+		t.Fatalf("lintADRs() failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("lintADRs() = %+v, want no dangling references to archived ADRs", issues)
+	}
+}
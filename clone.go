@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// isOwnSection reports the headings of sections that belong to the ADR they
+// are in and are left out of a copy: its links to other decisions and its
+// history.
+func isOwnSection(line string) bool {
+	return isRelationsHeading(line) || isDecisionLogHeading(line)
+}
+
+// withoutOwnSections drops the Relations and Decision Log sections from
+// lines, each up to the next heading of the same or a higher level or a "---"
+// rule, and the relation keys of the frontmatter.
+func withoutOwnSections(lines []string) []string {
+	var kept []string
+	end := frontmatterEnd(lines)
+	skipping, inFence := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if i > 0 && i < end && frontmatterRelationPattern.MatchString(trimmed) {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		} else if !inFence && (strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "## ") || trimmed == "---") {
+			skipping = isOwnSection(trimmed)
+		}
+		if !skipping {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// cloneADRContent turns the content of an existing ADR into the start of a
+// new one: the heading, status and date are set for the new ADR, and what
+// only applies to the original (Previous Status, Timestamp, relations and
+// the Decision Log) is dropped.
+func cloneADRContent(source, number, status, title, date, timestamp string) string {
+	lines := withoutOwnSections(strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n"))
+	for _, name := range []string{"Previous Status", "Timestamp"} {
+		lines = removeFields(lines, name)
+	}
+	content := strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+
+	content = setField(content, "Status", status)
+	content = setField(content, "Date", date)
+	if timestamp != "" {
+		content = insertFields(content, formatField(fieldBold, "Timestamp", timestamp))
+	}
+	if getHeadingLine(content) != "" {
+		return setHeading(content, number, title)
+	}
+	return updateTitle(content, number, title)
+}
+
+// cloneADR reads ADR from and returns its content as the start of ADR number.
+func cloneADR(from, number, status, title, date string, opts createOptions) (string, error) {
+	files, err := readDirWithRetry(adrDir)
+	if err != nil {
+		return "", err
+	}
+	sourceFile, ok := findADRFile(files, from)
+	if !ok {
+		return "", fmt.Errorf("ADR %s not found in %s", from, adrDir)
+	}
+	source, err := readFileWithRetry(filepath.Join(adrDir, sourceFile))
+	if err != nil {
+		return "", err
+	}
+	return wrapMarkdown(cloneADRContent(string(source), number, status, title, date, opts.timestamp), opts.wrap), nil
+}
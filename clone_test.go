@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCloneADRContent(t *testing.T) {
+	source := "---\ntags: [db]\nSuperseded-by: adr-009\n---\n\n# ADR 004: Use Postgres\n\n**Status**: Superseded  \n**Previous Status**: Accepted  \n**Date**: 2023-05-01\n\n## Context\n\nWe need a database.\n\n## Relations\n\n- Superseded by: ADR 009\n\n## Decision\n\nUse Postgres.\n\n## Decision Log\n\n- 2023-05-01: Proposed → Accepted — approved\n"
+
+	expected := "---\ntags: [db]\n---\n\n# ADR 010: Use Postgres for Analytics\n\n**Status**: Proposed  \n**Date**: 2024-06-01  \n\n## Context\n\nWe need a database.\n\n## Decision\n\nUse Postgres.\n"
+	if result := cloneADRContent(source, "010", "Proposed", "Use Postgres for Analytics", "2024-06-01", ""); result != expected {
+		t.Errorf("cloneADRContent() = %q, want %q", result, expected)
+	}
+}
+
+func TestRunCreateFrom(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	sourcePath := filepath.Join(tempDir, "adr-004-use-postgres.md")
+	source := "# ADR 004: Use Postgres\n\n**Status**: Accepted  \n**Date**: 2023-05-01\n\n## Context\n\nWe need a database.\n\n## Relations\n\n- Depends on: ADR 002\n"
+	if err := writeFile(sourcePath, source); err != nil {
+		t.Fatalf("Failed to create ADR: %v", err)
+	}
+
+	args := []string{"--dir", tempDir, "--number", "005", "--status", "Proposed", "--title", "Use Postgres for Analytics", "--from", "004"}
+	var code int
+	captureOutput(t, func() { code = runCreate(args) })
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0", code)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "adr-005-use-postgres-for-analytics.md"))
+	if err != nil {
+		t.Fatalf("ADR was not created: %v", err)
+	}
+	if getHeadingLine(string(content)) != "# ADR 005: Use Postgres for Analytics" || getCurrentStatus(string(content)) != "Proposed" {
+		t.Errorf("Copied ADR has the wrong heading or status:\n%s", content)
+	}
+	if len(parseRelations(string(content))) != 0 {
+		t.Errorf("Copied ADR kept the relations of ADR 004:\n%s", content)
+	}
+	if unchanged, _ := os.ReadFile(sourcePath); string(unchanged) != source {
+		t.Errorf("ADR 004 was modified:\n%s", unchanged)
+	}
+
+	args = []string{"--dir", tempDir, "--number", "006", "--status", "Proposed", "--title", "Nothing", "--from", "008"}
+	captureOutput(t, func() { code = runCreate(args) })
+	if code == 0 {
+		t.Error("runCreate() should fail when the ADR to copy doesn't exist")
+	}
+}
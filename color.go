@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/manifoldco/promptui"
+)
+
+// colorEnabled reports whether prompts may use ANSI styling: not when
+// NO_COLOR is set (https://no-color.org) or stdout is not a terminal.
+func colorEnabled() bool {
+	return os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+}
+
+// plainFuncMap replaces promptui's color helpers with functions that return
+// their input unstyled, so custom templates using them still parse.
+func plainFuncMap() template.FuncMap {
+	funcs := make(template.FuncMap, len(promptui.FuncMap))
+	for name := range promptui.FuncMap {
+		funcs[name] = func(v interface{}) string { return fmt.Sprint(v) }
+	}
+	return funcs
+}
+
+// promptTemplates returns nil (promptui's colored defaults) when colors are
+// enabled, and the same layout without escape codes otherwise.
+func promptTemplates() *promptui.PromptTemplates {
+	if colorEnabled() {
+		return nil
+	}
+	return &promptui.PromptTemplates{
+		Prompt:          "? {{ . }}: ",
+		Valid:           "✔ {{ . }}: ",
+		Invalid:         "✗ {{ . }}: ",
+		ValidationError: ">> {{ . }}",
+		Success:         "{{ . }}: ",
+		FuncMap:         plainFuncMap(),
+	}
+}
+
+// selectTemplates is promptTemplates for select prompts.
+func selectTemplates() *promptui.SelectTemplates {
+	if colorEnabled() {
+		return nil
+	}
+	return &promptui.SelectTemplates{
+		Label:    "? {{ . }}: ",
+		Active:   "▸ {{ . }}",
+		Inactive: "  {{ . }}",
+		Selected: "✔ {{ . }}",
+		FuncMap:  plainFuncMap(),
+	}
+}
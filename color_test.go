@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromptTemplatesNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	prompt, sel := promptTemplates(), selectTemplates()
+	if prompt == nil || sel == nil {
+		t.Fatal("NO_COLOR should replace promptui's colored templates")
+	}
+	for _, template := range []string{prompt.Prompt, prompt.Valid, prompt.Invalid, prompt.ValidationError, prompt.Success, sel.Label, sel.Active, sel.Inactive, sel.Selected} {
+		if strings.Contains(template, "\x1b") {
+			t.Errorf("Template %q contains an escape code", template)
+		}
+	}
+	bold := prompt.FuncMap["bold"].(func(interface{}) string)
+	if got := bold("Title"); got != "Title" {
+		t.Errorf("bold(%q) = %q, want it unstyled", "Title", got)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// companions are the suffixes of the companion files scaffolded next to new
+// ADRs, e.g. ".excalidraw" or "-notes.md", from the "companions" setting in
+// .adrgen.yaml. Each one is rendered from companion<suffix> in the ADR
+// directory.
+var companions []string
+
+func companionTemplatePath(suffix string) string {
+	return filepath.Join(adrDir, "companion"+suffix)
+}
+
+// companionPath is the companion file of the ADR at adrPath, named after it.
+func companionPath(adrPath, suffix string) string {
+	return strings.TrimSuffix(adrPath, ".md") + suffix
+}
+
+// isCompanionFile reports whether name is a companion file or template, so
+// "-notes.md" companions aren't mistaken for ADRs.
+func isCompanionFile(name string) bool {
+	for _, suffix := range companions {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// configuredCompanions reads the companion suffixes from the config file,
+// given either inline ("companions: .excalidraw, -notes.md") or as a list of
+// "- suffix" lines below "companions:".
+func configuredCompanions(content string) []string {
+	var suffixes []string
+	for _, value := range configList(content, "companions") {
+		suffix := strings.Trim(value, `"'`)
+		switch {
+		case suffix == "":
+		case suffix == ".md" || !strings.Contains(suffix, ".") || strings.ContainsAny(suffix, `/\`):
+			fmt.Fprintf(os.Stderr, "Warning: ignoring companion suffix %q in %s\n", suffix, configFile)
+		default:
+			suffixes = append(suffixes, suffix)
+		}
+	}
+	return suffixes
+}
+
+// planCompanions plans scaffolding the companion files of a new ADR from
+// their templates. Existing files are left alone.
+func planCompanions(adrPath string, vars map[string]string) ([]fileOp, error) {
+	var ops []fileOp
+	for _, suffix := range companions {
+		path := companionPath(adrPath, suffix)
+		if _, err := fsys.Stat(path); err == nil {
+			continue
+		}
+		template, err := fsys.ReadFile(companionTemplatePath(suffix))
+		if os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: no template %s for %s companions\n", companionTemplatePath(suffix), suffix)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		content, err := renderADRTemplate(string(template), vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", companionTemplatePath(suffix), err)
+		}
+		ops = append(ops, fileOp{Op: "create", Path: path, apply: func() error {
+			if err := withRetry(func() error { return writeFile(path, content) }); err != nil {
+				return fmt.Errorf("creating companion files: %w", err)
+			}
+			fmt.Printf("✅ Companion file created: %s\n", path)
+			return nil
+		}})
+	}
+	return ops, nil
+}
+
+// planCompanionMoves plans renaming the companion files of the ADR at from
+// along with it to to, or removing them when to is empty.
+func planCompanionMoves(from, to string) ([]fileOp, error) {
+	var ops []fileOp
+	for _, suffix := range companions {
+		source := companionPath(from, suffix)
+		content, err := fsys.ReadFile(source)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if to == "" {
+			ops = append(ops, fileOp{Op: "remove", Path: source, apply: func() error {
+				return withRetry(func() error { return fsys.Remove(source) })
+			}})
+			continue
+		}
+		target := companionPath(to, suffix)
+		ops = append(ops, fileOp{Op: "rename", Path: target, From: source, apply: func() error {
+			return moveCompanionFile(source, target, string(content))
+		}})
+	}
+	return ops, nil
+}
+
+// moveCompanionFile writes content to target and removes source, unless
+// they are the same file.
+func moveCompanionFile(source, target, content string) error {
+	if err := withRetry(func() error { return writeFile(target, content) }); err != nil {
+		return err
+	}
+	if source == target {
+		return nil
+	}
+	if err := withRetry(func() error { return fsys.Remove(source) }); err != nil {
+		return err
+	}
+	debugf("removed %s", source)
+	return nil
+}
+
+// moveCompanions renames the companion files of ADRs moving from one path to
+// another. Everything is read before anything is written, so ADRs swapping
+// names keep their own companions.
+func moveCompanions(moves map[string]string) error {
+	type pending struct {
+		from, to string
+		content  string
+	}
+	var files []pending
+	for from, to := range moves {
+		for _, suffix := range companions {
+			source := companionPath(from, suffix)
+			content, err := fsys.ReadFile(source)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			files = append(files, pending{source, companionPath(to, suffix), string(content)})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].from < files[j].from })
+
+	written := make(map[string]bool)
+	for _, file := range files {
+		if err := withRetry(func() error { return writeFile(file.to, file.content) }); err != nil {
+			return err
+		}
+		written[file.to] = true
+	}
+	for _, file := range files {
+		if written[file.from] {
+			continue
+		}
+		if err := withRetry(func() error { return fsys.Remove(file.from) }); err != nil {
+			return err
+		}
+		debugf("removed %s", file.from)
+	}
+	return nil
+}
+
+// removeCompanions deletes the companion files of the ADR at adrPath.
+func removeCompanions(adrPath string) error {
+	for _, suffix := range companions {
+		path := companionPath(adrPath, suffix)
+		if _, err := fsys.Stat(path); err != nil {
+			continue
+		}
+		if err := withRetry(func() error { return fsys.Remove(path) }); err != nil {
+			return err
+		}
+		debugf("removed %s", path)
+	}
+	return nil
+}
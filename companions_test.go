@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConfiguredCompanions(t *testing.T) {
+	inline := "dir: docs/adr\ncompanions: .excalidraw, \"-notes.md\"\n"
+	if got := configuredCompanions(inline); !reflect.DeepEqual(got, []string{".excalidraw", "-notes.md"}) {
+		t.Errorf("configuredCompanions(inline) = %q", got)
+	}
+	list := "companions:\n  - .excalidraw\n  - -notes.md\ndir: docs/adr\n"
+	if got := configuredCompanions(list); !reflect.DeepEqual(got, []string{".excalidraw", "-notes.md"}) {
+		t.Errorf("configuredCompanions(list) = %q", got)
+	}
+	captureOutput(t, func() {
+		if got := configuredCompanions("companions: .md, notes, ../x.md\n"); got != nil {
+			t.Errorf("configuredCompanions() kept invalid suffixes: %q", got)
+		}
+	})
+}
+
+func TestRunCreateCompanions(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalCompanions := adrDir, companions
+	defer func() { adrDir, companions = originalAdrDir, originalCompanions }()
+
+	files := map[string]string{
+		configFile:             "companions: .excalidraw, -notes.md\n",
+		"companion.excalidraw": `{"title": "{{title}}"}`,
+		"companion-notes.md":   "# Notes for ADR {{number}}\n",
+	}
+	for file, content := range files {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--number", "007", "--status", "Proposed", "--title", "Use Kafka"})
+	})
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+	expected := map[string]string{
+		"adr-007-use-kafka.excalidraw": `{"title": "Use Kafka"}`,
+		"adr-007-use-kafka-notes.md":   "# Notes for ADR 007\n",
+	}
+	for file, want := range expected {
+		if content, err := os.ReadFile(filepath.Join(tempDir, file)); err != nil || string(content) != want {
+			t.Errorf("%s = %q (%v), want %q", file, content, err, want)
+		}
+	}
+	if adrs, err := loadADRs(); err != nil || len(adrs) != 1 {
+		t.Errorf("loadADRs() = %+v (%v), want only the ADR itself", adrs, err)
+	}
+
+	// Retitling renames the companions along with the ADR
+	_, stderr = captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--number", "007", "--status", "Accepted", "--title", "Use Pulsar"})
+	})
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+	for _, file := range []string{"adr-007-use-pulsar.excalidraw", "adr-007-use-pulsar-notes.md"} {
+		if _, err := os.Stat(filepath.Join(tempDir, file)); err != nil {
+			t.Errorf("Companion %s missing after retitle: %v", file, err)
+		}
+	}
+	for file := range expected {
+		if _, err := os.Stat(filepath.Join(tempDir, file)); !os.IsNotExist(err) {
+			t.Errorf("Old companion %s was left behind", file)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+var commandNames = []string{"template", "completion", "relate", "sync-headings", "schema", "lint", "diff", "export", "reserve", "next", "index", "archive", "move", "merge", "digest", "list", "graph", "rollback", "serve", "new", "import", "open", "publish", "validate-template"}
+
+var subcommandNames = map[string][]string{
+	"template":   {"list", "init"},
+	"completion": {"bash", "zsh", "fish"},
+}
+
+type completionFlag struct {
+	Name   string
+	Usage  string
+	IsBool bool
+}
+
+func completionFlags() []completionFlag {
+	fs := flag.NewFlagSet("adrgen", flag.ContinueOnError)
+	registerFlags(fs, &createOptions{})
+
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, completionFlag{
+			Name:   f.Name,
+			Usage:  f.Usage,
+			IsBool: ok && boolFlag.IsBoolFlag(),
+		})
+	})
+	return flags
+}
+
+func flagNames(flags []completionFlag) string {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, "--"+f.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+func bashCompletion() string {
+	return `# bash completion for adrgen
+_adrgen() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "$prev" in
+        --number)
+            COMPREPLY=($(compgen -W "$(adrgen __complete numbers 2>/dev/null | cut -f1)" -- "$cur"))
+            return ;;
+        --status)
+            COMPREPLY=($(compgen -W "$(adrgen __complete statuses 2>/dev/null)" -- "$cur"))
+            return ;;
+        --template)
+            COMPREPLY=($(compgen -W "$(adrgen __complete templates 2>/dev/null)" -- "$cur"))
+            return ;;
+    esac
+
+    if [[ $COMP_CWORD -eq 1 && "$cur" != -* ]]; then
+        COMPREPLY=($(compgen -W "` + strings.Join(commandNames, " ") + `" -- "$cur"))
+        return
+    fi
+
+    case "${COMP_WORDS[1]}" in
+        template)
+            [[ $COMP_CWORD -eq 2 ]] && COMPREPLY=($(compgen -W "` + strings.Join(subcommandNames["template"], " ") + `" -- "$cur"))
+            return ;;
+        completion)
+            [[ $COMP_CWORD -eq 2 ]] && COMPREPLY=($(compgen -W "` + strings.Join(subcommandNames["completion"], " ") + `" -- "$cur"))
+            return ;;
+    esac
+
+    COMPREPLY=($(compgen -W "` + flagNames(completionFlags()) + `" -- "$cur"))
+}
+complete -F _adrgen adrgen
+`
+}
+
+func zshCompletion() string {
+	escape := strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`).Replace
+
+	var specs []string
+	for _, f := range completionFlags() {
+		spec := fmt.Sprintf("'--%s[%s]", f.Name, escape(f.Usage))
+		switch {
+		case f.IsBool:
+		case f.Name == "number":
+			spec += ":number:_adrgen_numbers"
+		case f.Name == "status":
+			spec += ":status:($(adrgen __complete statuses 2>/dev/null))"
+		case f.Name == "template":
+			spec += ":template:($(adrgen __complete templates 2>/dev/null))"
+		default:
+			spec += ":" + f.Name + ":"
+		}
+		specs = append(specs, spec+"'")
+	}
+
+	return `#compdef adrgen
+
+_adrgen_numbers() {
+    local -a numbers
+    numbers=(${(f)"$(adrgen __complete numbers 2>/dev/null | sed 's/:/\\:/g; s/	/:/')"})
+    _describe 'ADR number' numbers
+}
+
+_adrgen() {
+    if (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then
+        _values 'command' ` + strings.Join(commandNames, " ") + `
+        return
+    fi
+
+    case $words[2] in
+        template)
+            (( CURRENT == 3 )) && _values 'template command' ` + strings.Join(subcommandNames["template"], " ") + `
+            ;;
+        completion)
+            (( CURRENT == 3 )) && _values 'shell' ` + strings.Join(subcommandNames["completion"], " ") + `
+            ;;
+        *)
+            _arguments \
+                ` + strings.Join(specs, " \\\n                ") + `
+            ;;
+    esac
+}
+
+if [ "$funcstack[1]" = "_adrgen" ]; then
+    _adrgen "$@"
+else
+    compdef _adrgen adrgen
+fi
+`
+}
+
+func fishCompletion() string {
+	var b strings.Builder
+	b.WriteString("# fish completion for adrgen\n")
+	b.WriteString("complete -c adrgen -f\n")
+
+	commands := strings.Join(commandNames, " ")
+	fmt.Fprintf(&b, "complete -c adrgen -n 'not __fish_seen_subcommand_from %s' -a '%s'\n", commands, commands)
+
+	subcommands := make([]string, 0, len(subcommandNames))
+	for command := range subcommandNames {
+		subcommands = append(subcommands, command)
+	}
+	sort.Strings(subcommands)
+	for _, command := range subcommands {
+		fmt.Fprintf(&b, "complete -c adrgen -n '__fish_seen_subcommand_from %s' -a '%s'\n", command, strings.Join(subcommandNames[command], " "))
+	}
+
+	escape := strings.NewReplacer("'", `\'`).Replace
+	for _, f := range completionFlags() {
+		line := fmt.Sprintf("complete -c adrgen -n 'not __fish_seen_subcommand_from %s' -l %s -d '%s'", commands, f.Name, escape(f.Usage))
+		switch {
+		case f.IsBool:
+		case f.Name == "number":
+			line += " -x -a '(adrgen __complete numbers 2>/dev/null)'"
+		case f.Name == "status":
+			line += " -x -a '(adrgen __complete statuses 2>/dev/null)'"
+		case f.Name == "template":
+			line += " -x -a '(adrgen __complete templates 2>/dev/null)'"
+		default:
+			line += " -r"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func runCompletionCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: adrgen completion bash|zsh|fish")
+	}
+
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion())
+	case "zsh":
+		fmt.Print(zshCompletion())
+	case "fish":
+		fmt.Print(fishCompletion())
+	default:
+		return fmt.Errorf("unsupported shell %q (supported: bash, zsh, fish)", args[0])
+	}
+	return nil
+}
+
+// runCompleteCommand prints dynamic completion candidates for the generated
+// scripts, one per line, with an optional tab-separated description.
+func runCompleteCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: adrgen __complete numbers|statuses|templates")
+	}
+
+	discoverADRDir(nil)
+	switch args[0] {
+	case "numbers":
+		adrs, err := loadADRs()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		for _, adr := range adrs {
+			if adr.Number != "" {
+				fmt.Printf("%s\t%s\n", adr.Number, adr.Title)
+			}
+		}
+	case "statuses":
+		fmt.Println(strings.Join(statuses, "\n"))
+	case "templates":
+		templates, err := listTemplates()
+		if err != nil {
+			return err
+		}
+		for _, t := range templates {
+			fmt.Println(t.Name)
+		}
+	default:
+		return fmt.Errorf("unknown completion target %q", args[0])
+	}
+	return nil
+}
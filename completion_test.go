@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCompletionScriptsIncludeFlags(t *testing.T) {
+	scripts := map[string]string{
+		"bash": bashCompletion(),
+		"zsh":  zshCompletion(),
+		"fish": fishCompletion(),
+	}
+
+	for shell, script := range scripts {
+		for _, f := range completionFlags() {
+			if !strings.Contains(script, f.Name) {
+				t.Errorf("%s completion is missing flag --%s", shell, f.Name)
+			}
+		}
+		if !strings.Contains(script, "__complete numbers") {
+			t.Errorf("%s completion does not complete ADR numbers dynamically", shell)
+		}
+	}
+}
+
+func TestBashCompletionSyntax(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	cmd := exec.Command(bash, "-n")
+	cmd.Stdin = strings.NewReader(bashCompletion())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("bash completion has syntax errors: %v\n%s", err, output)
+	}
+}
+
+func TestRunCompletionCommandUnknownShell(t *testing.T) {
+	if err := runCompletionCommand([]string{"powershell"}); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvFile is the name the csv export target writes in its output directory.
+const csvFile = "adrs.csv"
+
+var csvColumns = []string{"number", "title", "status", "date", "tags"}
+
+// adrTitle prefers the title in the ADR's heading over the one derived from
+// its filename.
+func adrTitle(adr ADR) (string, error) {
+	content, err := readFileWithRetry(filepath.Join(adrDir, adr.Filename))
+	if err != nil {
+		return "", err
+	}
+	if title := getCurrentTitle(string(content)); title != "" {
+		return title, nil
+	}
+	return adr.Title, nil
+}
+
+func exportCSV(adrs []ADR, outDir string) error {
+	if err := ensureDir(outDir); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write(csvColumns)
+	for _, adr := range adrs {
+		if adr.Number == "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s has no ADR number and was left out\n", adr.Filename)
+			continue
+		}
+		title, err := adrTitle(adr)
+		if err != nil {
+			return err
+		}
+		w.Write([]string{adr.Number, title, adr.Status, adr.Date, strings.Join(adr.Tags, ", ")})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(outDir, csvFile), b.String())
+}
+
+type csvRow struct {
+	Line    int
+	Number  string
+	Title   string
+	Status  string
+	Date    string
+	Tags    []string
+	HasDate bool
+	HasTags bool
+}
+
+// readCSVRows parses a CSV file whose header names its columns. number,
+// title and status are required; date and tags are optional.
+func readCSVRows(r io.Reader) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty CSV file")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %q column", name)
+		}
+	}
+	value := func(record []string, name string) (string, bool) {
+		i, ok := columns[name]
+		if !ok {
+			return "", false
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+
+	var rows []csvRow
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := csvRow{Line: line}
+		row.Number, _ = value(record, "number")
+		row.Title, _ = value(record, "title")
+		row.Status, _ = value(record, "status")
+		row.Date, row.HasDate = value(record, "date")
+		var tags string
+		tags, row.HasTags = value(record, "tags")
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				row.Tags = append(row.Tags, tag)
+			}
+		}
+
+		if row.Title == "" {
+			return nil, fmt.Errorf("line %d: title is required", line)
+		}
+		if row.Status == "" {
+			return nil, fmt.Errorf("line %d: status is required", line)
+		}
+		if row.Number != "" {
+			num, err := strconv.Atoi(row.Number)
+			if err != nil || num < 0 {
+				return nil, fmt.Errorf("line %d: invalid number %q", line, row.Number)
+			}
+			row.Number = formatNumber(num)
+			if err := validateNumber(row.Number); err != nil {
+				return nil, fmt.Errorf("line %d: invalid number: %w", line, err)
+			}
+			if first, ok := seen[row.Number]; ok {
+				return nil, fmt.Errorf("line %d: number %s already used on line %d", line, row.Number, first)
+			}
+			seen[row.Number] = line
+		}
+		if row.Date != "" {
+			if _, err := parseDate(row.Date); err != nil {
+				return nil, fmt.Errorf("line %d: invalid date %q (expected YYYY-MM-DD)", line, row.Date)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+type importResult struct {
+	Created, Updated, Unchanged int
+}
+
+// importCSV creates or updates one ADR per row. Statuses are checked for
+// every row before anything is written; a status adrgen doesn't know is only
+// accepted when the ADR already has it.
+func importCSV(rows []csvRow) (importResult, error) {
+	var result importResult
+	existing, err := loadADRs()
+	if err != nil {
+		return result, err
+	}
+	byNumber := make(map[string]ADR)
+	for _, adr := range existing {
+		byNumber[adr.Number] = adr
+	}
+	for i, row := range rows {
+		if known, ok := normalizeStatus(row.Status); ok {
+			rows[i].Status = known
+		} else if adr, ok := byNumber[row.Number]; !ok || !strings.EqualFold(adr.Status, row.Status) {
+			return result, fmt.Errorf("line %d: invalid status %q (supported: %s)", row.Line, row.Status, strings.Join(statuses, ", "))
+		}
+	}
+
+	for _, row := range rows {
+		number := row.Number
+		if number == "" {
+			number = getNextADRNumber()
+		}
+		files, err := readDirWithRetry(adrDir)
+		if err != nil {
+			return result, err
+		}
+		name, found := findADRFile(files, number)
+		if !found {
+			if err := createFromCSV(number, row); err != nil {
+				return result, fmt.Errorf("line %d: %w", row.Line, err)
+			}
+			result.Created++
+			continue
+		}
+
+		changed, err := updateFromCSV(name, number, row)
+		if err != nil {
+			return result, fmt.Errorf("line %d: %w", row.Line, err)
+		}
+		if changed {
+			result.Updated++
+		} else {
+			result.Unchanged++
+		}
+	}
+	return result, nil
+}
+
+func createFromCSV(number string, row csvRow) error {
+	date := row.Date
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	content, err := newADRContent(number, row.Status, row.Title, date, createOptions{tags: row.Tags})
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(adrDir, adrFilename(number, row.Title))
+	if err := runPreWriteHook(path, content); err != nil {
+		return err
+	}
+	return withRetry(func() error { return writeFile(path, content) })
+}
+
+func updateFromCSV(name, number string, row csvRow) (bool, error) {
+	original, err := readFileWithRetry(filepath.Join(adrDir, name))
+	if err != nil {
+		return false, err
+	}
+	content := string(original)
+
+	if !strings.EqualFold(getCurrentStatus(content), row.Status) {
+		content = updateStatus(content, row.Status)
+	}
+	currentTitle := getCurrentTitle(content)
+	if currentTitle != row.Title {
+		content = updateTitle(content, number, row.Title)
+	}
+	if row.HasDate && row.Date != "" {
+		content = setField(content, "Date", row.Date)
+	}
+	if row.HasTags && strings.Join(extractTags(content), ", ") != strings.Join(row.Tags, ", ") {
+		content = setField(content, "Tags", strings.Join(row.Tags, ", "))
+	}
+
+	newName := name
+	if currentTitle != row.Title {
+		newName = renderFilename(number, row.Title, name)
+	}
+	if content == string(original) && newName == name {
+		return false, nil
+	}
+	if err := runPreWriteHook(filepath.Join(adrDir, newName), content); err != nil {
+		return false, err
+	}
+	changed := false
+	err = withRetry(func() error {
+		var err error
+		changed, err = writeFileIfChanged(filepath.Join(adrDir, newName), content)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if newName != name {
+		if err := withRetry(func() error { return fsys.Remove(filepath.Join(adrDir, name)) }); err != nil {
+			return true, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+func runImportCommand(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	registerDirFlags(fs)
+	fs.BoolVar(&noPreviousStatus, "no-previous-status", noPreviousStatus, "Update statuses in place without recording a Previous Status field")
+	fs.StringVar(&preWriteHook, "pre-write-hook", preWriteHook, "Shell command run before each ADR is written, with the file path as $1 and the content on stdin; a non-zero exit stops the import")
+	path := fs.String("csv", "", "CSV file with number, title, status and optional date and tags columns (- for stdin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if *path == "" {
+		return fmt.Errorf("--csv is required")
+	}
+
+	var input io.Reader = os.Stdin
+	if *path != "-" {
+		file, err := os.Open(*path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	rows, err := readCSVRows(input)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", *path, err)
+	}
+
+	if err := ensureDir(adrDir); err != nil {
+		return err
+	}
+	result, err := importCSV(rows)
+	if err != nil {
+		return err
+	}
+	if result.Created > 0 || result.Updated > 0 {
+		if err := withRetry(updateIndex); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("✅ Imported %d row(s): %d created, %d updated, %d unchanged\n", len(rows), result.Created, result.Updated, result.Unchanged)
+	return nil
+}
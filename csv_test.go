@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVRows(t *testing.T) {
+	rows, err := readCSVRows(strings.NewReader("Status,Number,Title\naccepted,7,Use Redis\n"))
+	if err != nil {
+		t.Fatalf("readCSVRows() failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Number != "007" || rows[0].Title != "Use Redis" || rows[0].HasDate {
+		t.Errorf("readCSVRows() = %+v", rows)
+	}
+
+	invalid := []string{
+		"number,title\n001,Use Redis\n",
+		"number,title,status\n001,,Accepted\n",
+		"number,title,status\nabc,Use Redis,Accepted\n",
+		"number,title,status,date\n001,Use Redis,Accepted,June\n",
+		"number,title,status\n001,Use Redis,Accepted\n1,Use Kafka,Accepted\n",
+	}
+	for _, input := range invalid {
+		if _, err := readCSVRows(strings.NewReader(input)); err == nil {
+			t.Errorf("readCSVRows(%q) should have failed", input)
+		}
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = filepath.Join(tempDir, "adr")
+	defer func() { adrDir = originalAdrDir }()
+	if err := ensureDir(adrDir); err != nil {
+		t.Fatalf("Failed to create ADR directory: %v", err)
+	}
+
+	testFiles := map[string]string{
+		"adr-001-use-go.md":    "# ADR 001: Use Go\n\n**Status**: accepted  \n**Date**: 2024-01-02  \n**Tags**: lang,backend\n",
+		"adr-002-use-kafka.md": "# ADR 002: Use Kafka, \"v3\"\n\n**Status**: Parked  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(adrDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	adrs, err := loadADRs()
+	if err != nil {
+		t.Fatalf("loadADRs() failed: %v", err)
+	}
+	outDir := filepath.Join(tempDir, "out")
+	if err := exportCSV(adrs, outDir); err != nil {
+		t.Fatalf("exportCSV() failed: %v", err)
+	}
+	exported, err := os.ReadFile(filepath.Join(outDir, csvFile))
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	expected := "number,title,status,date,tags\n001,Use Go,accepted,2024-01-02,\"lang, backend\"\n002,\"Use Kafka, \"\"v3\"\"\",Parked,,\n"
+	if string(exported) != expected {
+		t.Errorf("exportCSV() = %q, want %q", exported, expected)
+	}
+
+	rows, err := readCSVRows(strings.NewReader(string(exported)))
+	if err != nil {
+		t.Fatalf("readCSVRows() failed: %v", err)
+	}
+	result, err := importCSV(rows)
+	if err != nil {
+		t.Fatalf("importCSV() failed: %v", err)
+	}
+	if result != (importResult{Unchanged: 2}) {
+		t.Errorf("Round trip import = %+v, want everything unchanged", result)
+	}
+	for file, content := range testFiles {
+		if data, _ := os.ReadFile(filepath.Join(adrDir, file)); string(data) != content {
+			t.Errorf("%s changed on round trip: %q", file, data)
+		}
+	}
+
+	edited := "number,title,status\n001,Use Go Modules,Deprecated\n003,Use Redis,Proposed\n"
+	rows, err = readCSVRows(strings.NewReader(edited))
+	if err != nil {
+		t.Fatalf("readCSVRows() failed: %v", err)
+	}
+	result, err = importCSV(rows)
+	if err != nil {
+		t.Fatalf("importCSV() failed: %v", err)
+	}
+	if result != (importResult{Created: 1, Updated: 1}) {
+		t.Errorf("importCSV() = %+v, want one created and one updated", result)
+	}
+	content, err := os.ReadFile(filepath.Join(adrDir, "adr-001-use-go-modules.md"))
+	if err != nil {
+		t.Fatalf("Updated ADR was not renamed: %v", err)
+	}
+	if getCurrentStatus(string(content)) != "Deprecated" || getCurrentTitle(string(content)) != "Use Go Modules" {
+		t.Errorf("Updated ADR = %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(adrDir, "adr-003-use-redis.md")); err != nil {
+		t.Errorf("New ADR was not created: %v", err)
+	}
+
+	rows, _ = readCSVRows(strings.NewReader("number,title,status\n001,Use Go,Bogus\n"))
+	if _, err := importCSV(rows); err == nil {
+		t.Error("importCSV() should reject an unknown status that differs from the ADR's")
+	}
+}
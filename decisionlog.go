@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func isDecisionLogHeading(line string) bool {
+	heading, ok := strings.CutPrefix(line, "## ")
+	return ok && strings.EqualFold(strings.TrimSpace(heading), "Decision Log")
+}
+
+// appendDecisionLog records a dated note in the Decision Log section. The
+// entry names the status change, or just the status when it didn't change.
+// Entries are only ever appended, so they survive later status updates.
+func appendDecisionLog(content, date, previousStatus, status, note string) string {
+	change := status
+	if previousStatus != "" && previousStatus != status {
+		change = previousStatus + " → " + status
+	}
+	entry := fmt.Sprintf("- %s: %s — %s", date, change, strings.TrimSpace(note))
+	return appendToSection(content, isDecisionLogHeading, "## Decision Log", entry)
+}
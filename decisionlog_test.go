@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestAppendDecisionLog(t *testing.T) {
+	content := "# ADR 001: Use Redis\n\n**Status**: Accepted  \n\n## Context\n\nCaching.\n"
+
+	content = updateStatus(content, "Deprecated")
+	content = appendDecisionLog(content, "2024-05-01", "Accepted", "Deprecated", "superseded by new caching strategy")
+	expected := "# ADR 001: Use Redis\n\n**Status**: Deprecated  \n**Previous Status**: Accepted  \n\n## Context\n\nCaching.\n\n## Decision Log\n\n- 2024-05-01: Accepted → Deprecated — superseded by new caching strategy\n"
+	if content != expected {
+		t.Fatalf("appendDecisionLog() = %q, want %q", content, expected)
+	}
+
+	// Later status changes keep earlier notes
+	content = updateStatus(content, "Superseded")
+	content = appendDecisionLog(content, "2024-06-01", "Deprecated", "Superseded", "replaced by ADR 007")
+	expected = "# ADR 001: Use Redis\n\n**Status**: Superseded  \n**Previous Status**: Deprecated  \n\n## Context\n\nCaching.\n\n## Decision Log\n\n- 2024-05-01: Accepted → Deprecated — superseded by new caching strategy\n- 2024-06-01: Deprecated → Superseded — replaced by ADR 007\n"
+	if content != expected {
+		t.Errorf("appendDecisionLog() = %q, want %q", content, expected)
+	}
+}
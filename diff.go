@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+type adrDifference struct {
+	Number string `json:"number"`
+	Field  string `json:"field"`
+	Left   string `json:"left"`
+	Right  string `json:"right"`
+}
+
+type adrSummary struct {
+	Number string `json:"number"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+type adrDiff struct {
+	OnlyLeft  []adrSummary    `json:"onlyLeft"`
+	OnlyRight []adrSummary    `json:"onlyRight"`
+	Changed   []adrDifference `json:"changed"`
+}
+
+func (d adrDiff) Empty() bool {
+	return len(d.OnlyLeft) == 0 && len(d.OnlyRight) == 0 && len(d.Changed) == 0
+}
+
+func loadADRsFrom(dir string) ([]ADR, error) {
+	original := adrDir
+	adrDir = dir
+	defer func() { adrDir = original }()
+	return loadADRs()
+}
+
+// diffADRs compares two ADR sets by number. Unnumbered files are ignored.
+func diffADRs(left, right []ADR) adrDiff {
+	byNumber := func(adrs []ADR) map[string]ADR {
+		m := make(map[string]ADR)
+		for _, adr := range adrs {
+			if adr.Number != "" {
+				m[adr.Number] = adr
+			}
+		}
+		return m
+	}
+	leftADRs, rightADRs := byNumber(left), byNumber(right)
+
+	var numbers []string
+	for number := range leftADRs {
+		numbers = append(numbers, number)
+	}
+	for number := range rightADRs {
+		if _, ok := leftADRs[number]; !ok {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Strings(numbers)
+
+	d := adrDiff{OnlyLeft: []adrSummary{}, OnlyRight: []adrSummary{}, Changed: []adrDifference{}}
+	for _, number := range numbers {
+		l, inLeft := leftADRs[number]
+		r, inRight := rightADRs[number]
+		switch {
+		case !inRight:
+			d.OnlyLeft = append(d.OnlyLeft, adrSummary{number, l.Title, l.Status})
+		case !inLeft:
+			d.OnlyRight = append(d.OnlyRight, adrSummary{number, r.Title, r.Status})
+		default:
+			if l.Title != r.Title {
+				d.Changed = append(d.Changed, adrDifference{number, "title", l.Title, r.Title})
+			}
+			if l.Status != r.Status {
+				d.Changed = append(d.Changed, adrDifference{number, "status", l.Status, r.Status})
+			}
+		}
+	}
+	return d
+}
+
+func printADRDiff(d adrDiff, leftDir, rightDir string) {
+	if d.Empty() {
+		fmt.Println("No differences")
+		return
+	}
+	for _, adr := range d.OnlyLeft {
+		fmt.Printf("Only in %s: ADR %s %s (%s)\n", leftDir, adr.Number, adr.Title, adr.Status)
+	}
+	for _, adr := range d.OnlyRight {
+		fmt.Printf("Only in %s: ADR %s %s (%s)\n", rightDir, adr.Number, adr.Title, adr.Status)
+	}
+	for _, change := range d.Changed {
+		fmt.Printf("ADR %s %s: %q -> %q\n", change.Number, change.Field, change.Left, change.Right)
+	}
+}
+
+func runDiffCommand(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.StringVar(&separator, "separator", separator, "Separator between the prefix, number and title words in filenames")
+	fs.StringVar(&filenameTemplate, "filename-template", filenameTemplate, "Pattern for ADR filenames (default adr-{{number}}-{{slug}}.md)")
+	format := fs.String("format", "text", "Output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: adrgen diff [--format text|json] <dirA> <dirB>")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q (supported: text, json)", *format)
+	}
+
+	leftDir, rightDir := fs.Arg(0), fs.Arg(1)
+	left, err := loadADRsFrom(leftDir)
+	if err != nil {
+		return err
+	}
+	right, err := loadADRsFrom(rightDir)
+	if err != nil {
+		return err
+	}
+
+	d := diffADRs(left, right)
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(d)
+	}
+	printADRDiff(d, leftDir, rightDir)
+	return nil
+}
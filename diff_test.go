@@ -0,0 +1,36 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffADRs(t *testing.T) {
+	left := []ADR{
+		{Number: "001", Title: "Use Postgres", Status: "Accepted"},
+		{Number: "002", Title: "Use Kafka", Status: "Proposed"},
+		{Number: "003", Title: "Use gRPC", Status: "Accepted"},
+		{Title: "Notes"},
+	}
+	right := []ADR{
+		{Number: "001", Title: "Use Postgres", Status: "Accepted"},
+		{Number: "002", Title: "Use Pulsar", Status: "Accepted"},
+		{Number: "004", Title: "Use OpenAPI", Status: "Proposed"},
+	}
+
+	expected := adrDiff{
+		OnlyLeft:  []adrSummary{{"003", "Use gRPC", "Accepted"}},
+		OnlyRight: []adrSummary{{"004", "Use OpenAPI", "Proposed"}},
+		Changed: []adrDifference{
+			{"002", "title", "Use Kafka", "Use Pulsar"},
+			{"002", "status", "Proposed", "Accepted"},
+		},
+	}
+
+	if result := diffADRs(left, right); !reflect.DeepEqual(result, expected) {
+		t.Errorf("diffADRs() = %+v, want %+v", result, expected)
+	}
+	if !diffADRs(left[:1], right[:1]).Empty() {
+		t.Error("diffADRs() of identical sets should be empty")
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var decisionLogEntryPattern = regexp.MustCompile(`^[-*]\s+(\d{4}-\d{2}-\d{2}):\s*(.+)$`)
+
+// statusChange is a Decision Log entry: the day an ADR moved to Status, from
+// From when the entry names it.
+type statusChange struct {
+	Date   time.Time
+	From   string
+	Status string
+}
+
+// parseDecisionLog reads the entries appendDecisionLog writes,
+// "- 2024-06-03: Proposed → Accepted — note", in the Decision Log section.
+func parseDecisionLog(content string) []statusChange {
+	var changes []statusChange
+	inSection := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			inSection = isDecisionLogHeading(trimmed)
+			continue
+		}
+		match := decisionLogEntryPattern.FindStringSubmatch(trimmed)
+		if !inSection || match == nil {
+			continue
+		}
+		date, err := parseDate(match[1])
+		if err != nil {
+			continue
+		}
+		change, _, _ := strings.Cut(match[2], " — ")
+		var from string
+		if before, after, ok := strings.Cut(change, "→"); ok {
+			from, change = strings.TrimSpace(before), after
+		}
+		if status, ok := normalizeStatus(strings.TrimSpace(change)); ok {
+			changes = append(changes, statusChange{Date: date, From: from, Status: status})
+		}
+	}
+	return changes
+}
+
+// renderDigest summarizes the ADRs created, accepted and deprecated within
+// [since, until] as Markdown for pasting into a message. Status changes come
+// from the Decision Log, so ADRs without one only show up as new.
+func renderDigest(since, until time.Time) (string, error) {
+	adrs, err := loadADRs()
+	if err != nil {
+		return "", err
+	}
+
+	inWindow := func(t time.Time) bool { return !t.Before(since) && !t.After(until) }
+	entry := func(adr ADR, detail string) string {
+		label := adr.Title
+		if adr.Number != "" {
+			label = fmt.Sprintf("ADR %s: %s", adr.Number, adr.Title)
+		}
+		return fmt.Sprintf("- [%s](%s) (%s)", label, adr.Filename, detail)
+	}
+
+	var created, accepted, deprecated []string
+	for _, adr := range adrs {
+		if date, err := adrTime(adr); err == nil && inWindow(date) {
+			created = append(created, entry(adr, cmp.Or(adr.Status, "no status")))
+		}
+		content, err := readFileWithRetry(filepath.Join(adrDir, adr.Filename))
+		if err != nil {
+			return "", err
+		}
+		for _, change := range parseDecisionLog(string(content)) {
+			if !inWindow(change.Date) {
+				continue
+			}
+			switch change.Status {
+			case "Accepted":
+				accepted = append(accepted, entry(adr, "on "+change.Date.Format("2006-01-02")))
+			case "Deprecated":
+				deprecated = append(deprecated, entry(adr, "on "+change.Date.Format("2006-01-02")))
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## ADR digest: %s to %s\n", since.Format("2006-01-02"), until.Format("2006-01-02"))
+	if len(created)+len(accepted)+len(deprecated) == 0 {
+		b.WriteString("\nNo new or changed ADRs.\n")
+		return b.String(), nil
+	}
+	for _, section := range []struct {
+		heading string
+		entries []string
+	}{
+		{"🆕 New", created},
+		{"✅ Accepted", accepted},
+		{"⚠️ Deprecated", deprecated},
+	} {
+		if len(section.entries) > 0 {
+			fmt.Fprintf(&b, "\n### %s\n\n%s\n", section.heading, strings.Join(section.entries, "\n"))
+		}
+	}
+	return b.String(), nil
+}
+
+func runDigestCommand(args []string) error {
+	fs := flag.NewFlagSet("digest", flag.ContinueOnError)
+	registerDirFlags(fs)
+	sinceFlag := fs.String("since", "", "Start of the window (YYYY-MM-DD, default a week before --until)")
+	untilFlag := fs.String("until", "", "End of the window (YYYY-MM-DD, inclusive, default today)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+
+	until, err := parseDateBound(cmp.Or(*untilFlag, time.Now().Format("2006-01-02")), true)
+	if err != nil {
+		return fmt.Errorf("invalid --until %q (expected YYYY-MM-DD or an RFC3339 timestamp)", *untilFlag)
+	}
+	since := time.Date(until.Year(), until.Month(), until.Day()-6, 0, 0, 0, 0, until.Location())
+	if *sinceFlag != "" {
+		if since, err = parseDateBound(*sinceFlag, false); err != nil {
+			return fmt.Errorf("invalid --since %q (expected YYYY-MM-DD or an RFC3339 timestamp)", *sinceFlag)
+		}
+	}
+	if until.Before(since) {
+		return fmt.Errorf("--until is before --since")
+	}
+
+	digest, err := renderDigest(since, until)
+	if err != nil {
+		return err
+	}
+	fmt.Print(digest)
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderDigest(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"adr-001-use-go.md":    "# ADR 001: Use Go\n\n**Status**: Accepted  \n**Date**: 2024-01-10\n\n## Decision Log\n\n- 2024-01-10: Proposed — drafted\n- 2024-06-03: Proposed → Accepted — approved by the board\n",
+		"adr-002-use-soap.md":  "# ADR 002: Use SOAP\n\n**Status**: Deprecated  \n**Date**: 2020-01-01\n\n## Decision Log\n\n- 2024-06-05: Accepted → Deprecated — REST everywhere\n- 2024-05-01: Accepted — kept\n",
+		"adr-003-use-kafka.md": "# ADR 003: Use Kafka\n\n**Status**: Proposed  \n**Date**: 2024-06-04\n",
+		"adr-004-use-rust.md":  "# ADR 004: Use Rust\n\n**Status**: Accepted  \n**Date**: 2024-05-01\n\n## Decision Log\n\n- 2024-05-02: Proposed → Accepted — earlier\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	since, _ := parseDateBound("2024-06-01", false)
+	until, _ := parseDateBound("2024-06-07", true)
+	digest, err := renderDigest(since, until)
+	if err != nil {
+		t.Fatalf("renderDigest() failed: %v", err)
+	}
+	expected := "## ADR digest: 2024-06-01 to 2024-06-07\n" +
+		"\n### 🆕 New\n\n- [ADR 003: Use Kafka](adr-003-use-kafka.md) (Proposed)\n" +
+		"\n### ✅ Accepted\n\n- [ADR 001: Use Go](adr-001-use-go.md) (on 2024-06-03)\n" +
+		"\n### ⚠️ Deprecated\n\n- [ADR 002: Use Soap](adr-002-use-soap.md) (on 2024-06-05)\n"
+	if digest != expected {
+		t.Errorf("renderDigest() = %q, want %q", digest, expected)
+	}
+
+	since, _ = parseDateBound("2023-01-01", false)
+	until, _ = parseDateBound("2023-01-07", true)
+	if digest, _ = renderDigest(since, until); digest != "## ADR digest: 2023-01-01 to 2023-01-07\n\nNo new or changed ADRs.\n" {
+		t.Errorf("renderDigest() for a quiet week = %q", digest)
+	}
+}
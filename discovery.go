@@ -0,0 +1,203 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// configFile marks a project root for directory discovery. A "dir: <path>"
+// line in it names the ADR directory relative to the file; other settings
+// are read by loadConfig.
+const configFile = ".adrgen.yaml"
+
+const defaultADRDir = "docs/adr"
+
+var noDiscovery = false
+
+// discoverADRDir points adrDir at the ADR directory of the enclosing project
+// when it is still the default and --dir wasn't given, so the tool works from
+// any subdirectory. fs may be nil for commands without flags. The settings
+// in the config file are loaded for whichever directory is used.
+func discoverADRDir(fs *flag.FlagSet) {
+	defer loadConfig(fs)
+	if noDiscovery || adrDir != defaultADRDir {
+		return
+	}
+	explicit := false
+	if fs != nil {
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "dir" {
+				explicit = true
+			}
+		})
+	}
+	if explicit {
+		return
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	dir, ok := findADRDir(cwd)
+	if !ok {
+		debugf("no ADR directory found above %s, using %s", cwd, adrDir)
+		return
+	}
+	if rel, err := filepath.Rel(cwd, dir); err == nil {
+		dir = rel
+	}
+	debugf("discovered ADR directory %s", dir)
+	adrDir = dir
+}
+
+// findADRDir walks up from start looking for a config file or an existing
+// default ADR directory. Like git, it doesn't search past a repository root.
+func findADRDir(start string) (string, bool) {
+	for dir := start; ; dir = filepath.Dir(dir) {
+		if content, err := os.ReadFile(filepath.Join(dir, configFile)); err == nil {
+			adrPath := configuredDir(string(content))
+			if !filepath.IsAbs(adrPath) {
+				adrPath = filepath.Join(dir, adrPath)
+			}
+			return adrPath, true
+		}
+		if info, err := os.Stat(filepath.Join(dir, defaultADRDir)); err == nil && info.IsDir() {
+			return filepath.Join(dir, defaultADRDir), true
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", false
+		}
+		if filepath.Dir(dir) == dir {
+			return "", false
+		}
+	}
+}
+
+func configuredDir(content string) string {
+	if value, ok := configValue(content, "dir"); ok {
+		return filepath.FromSlash(value)
+	}
+	return defaultADRDir
+}
+
+// configList reads a list setting from the config file, given either inline
+// ("key: a, b") or as "- item" lines below "key:". Items are trimmed; empty
+// ones are dropped.
+func configList(content, key string) []string {
+	var values []string
+	inList := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if value, ok := strings.CutPrefix(trimmed, key+":"); ok {
+			inList = strings.TrimSpace(value) == ""
+			values = append(values, strings.Split(value, ",")...)
+			continue
+		}
+		if !inList {
+			continue
+		}
+		if value, ok := strings.CutPrefix(trimmed, "- "); ok {
+			values = append(values, value)
+		} else if trimmed != "" {
+			inList = false
+		}
+	}
+
+	var items []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			items = append(items, value)
+		}
+	}
+	return items
+}
+
+// configValue returns the value of a top-level "key: value" line.
+func configValue(content, key string) (string, bool) {
+	for _, line := range strings.Split(content, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), key+":"); ok {
+			if value = strings.Trim(strings.TrimSpace(value), `"'`); value != "" {
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// loadConfig applies the settings of the config file in or above the ADR
+// directory, stopping at the repository root. Flags given on the command line
+// take precedence.
+func loadConfig(fs *flag.FlagSet) {
+	companions, templateDefaults = nil, nil
+	start, err := filepath.Abs(adrDir)
+	if err != nil {
+		return
+	}
+	for dir := start; ; dir = filepath.Dir(dir) {
+		if content, err := os.ReadFile(filepath.Join(dir, configFile)); err == nil {
+			applyConfig(string(content), fs)
+			return
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil || filepath.Dir(dir) == dir {
+			return
+		}
+	}
+}
+
+func applyConfig(content string, fs *flag.FlagSet) {
+	companions = configuredCompanions(content)
+	debugf("companion files: %s", strings.Join(companions, ", "))
+	templateDefaults = configuredTemplateDefaults(content)
+
+	explicit := make(map[string]bool)
+	if fs != nil {
+		fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	}
+	if value, ok := configValue(content, "default-status"); ok && !explicit["default-status"] {
+		defaultStatus = value
+	}
+	if sections := configList(content, "status-sections"); len(sections) > 0 && !explicit["status-sections"] {
+		indexStatusSections = strings.Join(sections, ",")
+	}
+	if value, ok := configValue(content, "relation-format"); ok && !explicit["relation-format"] {
+		relationFormat = value
+	}
+	if value, ok := configValue(content, "ignore"); ok && !explicit["ignore"] {
+		ignorePatterns = value
+	}
+	if value, ok := configValue(content, "superseded-label"); ok && !explicit["superseded-label"] {
+		supersededLabel = value
+	}
+	if value, ok := configValue(content, "index-heading"); ok && !explicit["index-heading"] {
+		indexHeading = value
+	}
+	if value, ok := configValue(content, "empty-index-message"); ok && !explicit["empty-index-message"] {
+		indexEmptyMessage = value
+	}
+	if value, ok := configValue(content, "index-header"); ok && !explicit["index-header"] {
+		indexHeader = value
+	}
+	if value, ok := configValue(content, "template-engine"); ok && !explicit["template-engine"] {
+		templateEngine = value
+	}
+	if value, ok := configValue(content, "number-format"); ok && !explicit["number-format"] {
+		if slices.Contains(numberFormatNames, value) {
+			numberFormat = value
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring number-format %q in %s (supported: %s)\n", value, configFile, strings.Join(numberFormatNames, ", "))
+		}
+	}
+	if value, ok := configValue(content, "base"); ok && !explicit["base-number"] {
+		if n, err := strconv.Atoi(value); err == nil {
+			baseNumber = n
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring base %q in %s: not a number\n", value, configFile)
+		}
+	}
+}
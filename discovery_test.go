@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindADRDir(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "services", "api", "internal")
+	for _, dir := range []string{filepath.Join(root, "docs", "adr"), nested} {
+		if err := ensureDir(dir); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	if dir, ok := findADRDir(nested); !ok || dir != filepath.Join(root, "docs", "adr") {
+		t.Errorf("findADRDir() = %q, %v, want the project's docs/adr", dir, ok)
+	}
+
+	// A config file closer to the working directory wins
+	service := filepath.Join(root, "services", "api")
+	if err := writeFile(filepath.Join(service, configFile), "dir: decisions\n"); err != nil {
+		t.Fatalf("Failed to create config file: %v", err)
+	}
+	if dir, ok := findADRDir(nested); !ok || dir != filepath.Join(service, "decisions") {
+		t.Errorf("findADRDir() = %q, %v, want the configured directory", dir, ok)
+	}
+
+	// The search stops at a repository root
+	repo := filepath.Join(root, "vendor", "lib")
+	if err := ensureDir(filepath.Join(repo, ".git")); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	if dir, ok := findADRDir(repo); ok {
+		t.Errorf("findADRDir() = %q, should not search past a .git directory", dir)
+	}
+}
+
+func TestDiscoverADRDir(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "cmd")
+	for _, dir := range []string{filepath.Join(root, "docs", "adr"), nested} {
+		if err := ensureDir(dir); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() failed: %v", err)
+	}
+	if err := os.Chdir(nested); err != nil {
+		t.Fatalf("Chdir() failed: %v", err)
+	}
+	originalAdrDir, originalNoDiscovery := adrDir, noDiscovery
+	defer func() {
+		os.Chdir(cwd)
+		adrDir, noDiscovery = originalAdrDir, originalNoDiscovery
+	}()
+
+	discover := func(args ...string) string {
+		adrDir, noDiscovery = defaultADRDir, false
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		registerDirFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		discoverADRDir(fs)
+		return adrDir
+	}
+
+	if dir := discover(); dir != filepath.Join("..", "docs", "adr") {
+		t.Errorf("adrDir = %q, want the discovered directory", dir)
+	}
+	if dir := discover("--no-discovery"); dir != defaultADRDir {
+		t.Errorf("adrDir = %q with --no-discovery, want %q", dir, defaultADRDir)
+	}
+	if dir := discover("--dir", defaultADRDir); dir != defaultADRDir {
+		t.Errorf("adrDir = %q with an explicit --dir, want %q", dir, defaultADRDir)
+	}
+}
+
+func TestApplyConfigDefaultStatus(t *testing.T) {
+	originalDefault, originalCompanions := defaultStatus, companions
+	defer func() { defaultStatus, companions = originalDefault, originalCompanions }()
+
+	defaultStatus = ""
+	applyConfig("dir: docs/adr\ndefault-status: Proposed\n", nil)
+	if defaultStatus != "Proposed" {
+		t.Errorf("defaultStatus = %q, want the configured Proposed", defaultStatus)
+	}
+
+	// A --default-status flag wins over the config file
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var opts createOptions
+	registerFlags(fs, &opts)
+	if err := fs.Parse([]string{"--default-status", "Accepted"}); err != nil {
+		t.Fatal(err)
+	}
+	applyConfig("default-status: Proposed\n", fs)
+	if defaultStatus != "Accepted" {
+		t.Errorf("defaultStatus = %q, want the flag's Accepted", defaultStatus)
+	}
+}
+
+func TestApplyConfigStatusSections(t *testing.T) {
+	originalSections, originalCompanions := indexStatusSections, companions
+	defer func() { indexStatusSections, companions = originalSections, originalCompanions }()
+
+	applyConfig("status-sections:\n  - Accepted\n  - Superseded: collapsed\n  - Rejected: hidden\ndir: docs/adr\n", nil)
+	if want := "Accepted,Superseded: collapsed,Rejected: hidden"; indexStatusSections != want {
+		t.Errorf("indexStatusSections = %q, want %q", indexStatusSections, want)
+	}
+	sections, err := parseStatusSections(indexStatusSections)
+	if err != nil {
+		t.Fatalf("parseStatusSections() failed: %v", err)
+	}
+	if len(sections) != 3 || sections[1] != (statusSection{Status: "Superseded", Mode: sectionCollapsed}) {
+		t.Errorf("parseStatusSections() = %+v", sections)
+	}
+}
+
+func TestApplyConfigTemplateDefaults(t *testing.T) {
+	originalDefaults, originalCompanions := templateDefaults, companions
+	defer func() { templateDefaults, companions = originalDefaults, originalCompanions }()
+
+	_, stderr := captureOutput(t, func() {
+		applyConfig("template-defaults:\n  - ticket=ARCH-\n  - team: \"Platform\"\n  - bad key=x\ndir: docs/adr\n", nil)
+	})
+	if len(templateDefaults) != 2 || templateDefaults["ticket"] != "ARCH-" || templateDefaults["team"] != "Platform" {
+		t.Errorf("templateDefaults = %v", templateDefaults)
+	}
+	if !strings.Contains(stderr, "bad key=x") {
+		t.Errorf("stderr = %q, want a warning about the invalid entry", stderr)
+	}
+}
+
+func TestApplyConfigNumberFormat(t *testing.T) {
+	originalFormat, originalCompanions := numberFormat, companions
+	defer func() { numberFormat, companions = originalFormat, originalCompanions }()
+
+	numberFormat = "padded"
+	applyConfig("dir: docs/adr\nnumber-format: roman\n", nil)
+	if numberFormat != "roman" {
+		t.Errorf("numberFormat = %q, want the configured roman", numberFormat)
+	}
+
+	// A --number-format flag wins over the config file
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	registerDirFlags(fs)
+	if err := fs.Parse([]string{"--number-format", "decimal"}); err != nil {
+		t.Fatal(err)
+	}
+	applyConfig("number-format: roman\n", fs)
+	if numberFormat != "decimal" {
+		t.Errorf("numberFormat = %q, want the flag's decimal", numberFormat)
+	}
+
+	_, stderr := captureOutput(t, func() { applyConfig("number-format: hex\n", nil) })
+	if numberFormat != "decimal" || !strings.Contains(stderr, "hex") {
+		t.Errorf("numberFormat = %q, stderr = %q, want an unknown format ignored with a warning", numberFormat, stderr)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+)
+
+const driversHeading = "## Decision Drivers"
+
+// stringList collects the values of a repeatable flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func isDriversHeading(line string) bool {
+	heading, ok := strings.CutPrefix(line, "## ")
+	return ok && strings.EqualFold(strings.TrimSpace(heading), "Decision Drivers")
+}
+
+// driversSection returns the line range of the Decision Drivers section: the
+// heading and everything up to the next heading or rule.
+func driversSection(lines []string) (int, int, bool) {
+	for i, line := range lines {
+		if !isDriversHeading(strings.TrimSpace(line)) {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) {
+			trimmed := strings.TrimSpace(lines[end])
+			if strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+				break
+			}
+			end++
+		}
+		return i, end, true
+	}
+	return 0, 0, false
+}
+
+// withDrivers lists drivers under the Decision Drivers section, adding the
+// section before the Decision section when the template has none. Without
+// drivers an empty Decision Drivers section is dropped.
+func withDrivers(content string, drivers []string) string {
+	lines := strings.Split(content, "\n")
+	if len(drivers) == 0 {
+		start, end, ok := driversSection(lines)
+		if !ok {
+			return content
+		}
+		for _, line := range lines[start+1 : end] {
+			if strings.TrimSpace(line) != "" {
+				return content
+			}
+		}
+		return strings.Join(append(lines[:start], lines[end:]...), "\n")
+	}
+
+	bullets := make([]string, len(drivers))
+	for i, driver := range drivers {
+		bullets[i] = "- " + strings.TrimSpace(driver)
+	}
+	if _, _, ok := driversSection(lines); !ok {
+		for i, line := range lines {
+			if strings.EqualFold(strings.TrimSpace(line), "## Decision") {
+				section := append([]string{driversHeading, ""}, bullets...)
+				section = append(section, "")
+				return strings.Join(append(lines[:i], append(section, lines[i:]...)...), "\n")
+			}
+		}
+	}
+	for _, bullet := range bullets {
+		content = appendToSection(content, isDriversHeading, driversHeading, bullet)
+	}
+	return content
+}
+
+// parseDrivers returns the bullet entries of the Decision Drivers section.
+func parseDrivers(content string) []string {
+	lines := strings.Split(content, "\n")
+	start, end, ok := driversSection(lines)
+	if !ok {
+		return nil
+	}
+	var drivers []string
+	for _, line := range lines[start+1 : end] {
+		trimmed := strings.TrimSpace(line)
+		if driver, ok := strings.CutPrefix(trimmed, "- "); ok {
+			drivers = append(drivers, strings.TrimSpace(driver))
+		} else if driver, ok := strings.CutPrefix(trimmed, "* "); ok {
+			drivers = append(drivers, strings.TrimSpace(driver))
+		}
+	}
+	return drivers
+}
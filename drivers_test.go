@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithDrivers(t *testing.T) {
+	template := "# ADR 001: Use Kafka\n\n## Context\n\nText.\n\n## Decision\n\nText.\n"
+
+	content := withDrivers(template, []string{"Throughput", " Operational cost "})
+	want := "# ADR 001: Use Kafka\n\n## Context\n\nText.\n\n## Decision Drivers\n\n- Throughput\n- Operational cost\n\n## Decision\n\nText.\n"
+	if content != want {
+		t.Errorf("withDrivers() = %q, want %q", content, want)
+	}
+	if drivers := parseDrivers(content); !reflect.DeepEqual(drivers, []string{"Throughput", "Operational cost"}) {
+		t.Errorf("parseDrivers() = %q", drivers)
+	}
+
+	// A template's own section is filled in, and dropped when left empty
+	madr := "# ADR 001: Use Kafka\n\n## Decision Drivers\n\n## Decision\n\nText.\n"
+	if content := withDrivers(madr, []string{"Latency"}); !strings.Contains(content, "## Decision Drivers\n\n- Latency\n\n## Decision\n") {
+		t.Errorf("withDrivers() = %q, want the driver in the existing section", content)
+	}
+	if content := withDrivers(madr, nil); content != "# ADR 001: Use Kafka\n\n## Decision\n\nText.\n" {
+		t.Errorf("withDrivers() without drivers = %q, want the section omitted", content)
+	}
+	if content := withDrivers(template, nil); content != template {
+		t.Errorf("withDrivers() changed a template without drivers: %q", content)
+	}
+}
+
+func TestFilterADRsByDriver(t *testing.T) {
+	adrs := []ADR{
+		{Number: "001", Drivers: []string{"Low latency"}},
+		{Number: "002", Drivers: []string{"Cost"}},
+		{Number: "003"},
+	}
+	filtered := filterADRsByDriver(adrs, "LATENCY")
+	if len(filtered) != 1 || filtered[0].Number != "001" {
+		t.Errorf("filterADRsByDriver() = %+v, want ADR 001", filtered)
+	}
+}
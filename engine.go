@@ -0,0 +1,106 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// templateEngine renders ADR and companion templates: "simple" fills
+// {{key}} placeholders, "gotemplate" runs them through text/template. Empty
+// picks gotemplate for templates that use its actions.
+var templateEngine = ""
+
+var templateEngines = []string{"simple", "gotemplate"}
+
+// goTemplateAction matches the text/template actions that a simple template
+// never contains, such as {{.Title}}, {{range .Tags}} or {{- if ...}}.
+var goTemplateAction = regexp.MustCompile(`\{\{-?\s*(\.|\$|(range|if|with|else|end|template|block|define|printf)\b)`)
+
+// usesGoTemplate reports whether tmpl is rendered with text/template.
+// Actions in fenced code blocks, such as an example of a Go template, don't
+// count.
+func usesGoTemplate(tmpl string) bool {
+	switch templateEngine {
+	case "gotemplate":
+		return true
+	case "simple":
+		return false
+	}
+	return goTemplateAction.MatchString(withoutCodeFences(tmpl))
+}
+
+// withoutCodeFences drops the fenced code blocks of a Markdown text.
+func withoutCodeFences(text string) string {
+	var kept []string
+	fence := ""
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+		case fence != "" && strings.HasPrefix(trimmed, fence):
+			fence = ""
+		case fence == "":
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// templateData is what a text/template sees: every placeholder under its own
+// name and capitalized ({{.title}} and {{.Title}}), with .Tags as a list.
+// Placeholders adrgen fills are always there, empty when unset.
+func templateData(vars map[string]string) map[string]any {
+	data := make(map[string]any, 2*(len(vars)+len(builtinPlaceholders))+1)
+	set := func(key, value string) {
+		first, size := utf8.DecodeRuneInString(key)
+		data[key], data[string(unicode.ToUpper(first))+key[size:]] = value, value
+	}
+	for _, key := range builtinPlaceholders {
+		set(key, "")
+	}
+	for key, value := range vars {
+		set(key, value)
+	}
+	var tags []string
+	for _, tag := range strings.Split(vars["tags"], ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	data["Tags"] = tags
+	return data
+}
+
+// renderADRTemplate renders tmpl with vars using the engine it expects.
+// text/template fails on a key vars doesn't have, where the simple engine
+// leaves the placeholder in place.
+func renderADRTemplate(tmpl string, vars map[string]string) (string, error) {
+	if !usesGoTemplate(tmpl) {
+		return renderTemplateVars(tmpl, vars), nil
+	}
+	parsed, err := template.New("adr").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := parsed.Execute(&b, templateData(vars)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// templateUses reports whether tmpl fills the placeholder key, as {{key}} or
+// as .key or .Key in a text/template.
+func templateUses(tmpl, key string) bool {
+	if strings.Contains(tmpl, "{{"+key+"}}") {
+		return true
+	}
+	if !usesGoTemplate(tmpl) {
+		return false
+	}
+	return regexp.MustCompile(`(?i)\.` + regexp.QuoteMeta(key) + `\b`).MatchString(tmpl)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestUsesGoTemplate(t *testing.T) {
+	originalEngine := templateEngine
+	defer func() { templateEngine = originalEngine }()
+
+	tests := []struct {
+		engine   string
+		template string
+		expected bool
+	}{
+		{"", "# ADR {{number}}: {{title}}\n", false},
+		{"", "# ADR {{.Number}}: {{.Title}}\n", true},
+		{"", "{{- range .Tags}}- {{.}}\n{{end}}", true},
+		{"", "{{if .author}}By {{.author}}{{end}}", true},
+		{"", "# ADR {{number}}: {{title}}\n\n```yaml\nimage: {{ .Values.image }}\n```\n", false},
+		{"", "# ADR {{number}}\n\n~~~\n{{range .Items}}{{end}}\n~~~\n\nBy {{.Author}}\n", true},
+		{"simple", "# ADR {{.Number}}\n", false},
+		{"gotemplate", "# ADR {{number}}\n", true},
+	}
+	for _, test := range tests {
+		templateEngine = test.engine
+		if result := usesGoTemplate(test.template); result != test.expected {
+			t.Errorf("usesGoTemplate(%q) with engine %q = %v, want %v", test.template, test.engine, result, test.expected)
+		}
+	}
+}
+
+func TestRenderADRTemplateGoTemplate(t *testing.T) {
+	originalEngine := templateEngine
+	templateEngine = ""
+	defer func() { templateEngine = originalEngine }()
+
+	template := "# ADR {{.Number}}: {{.title}}\n{{range .Tags}}\n- {{.}}{{end}}\n{{if .Author}}By {{.Author}}{{else}}Unattributed{{end}} ({{.ticket}})\n"
+	vars := map[string]string{"number": "007", "title": "Use Kafka", "tags": "events, streaming", "ticket": "ARCH-1"}
+	result, err := renderADRTemplate(template, vars)
+	if err != nil {
+		t.Fatalf("renderADRTemplate() failed: %v", err)
+	}
+	expected := "# ADR 007: Use Kafka\n\n- events\n- streaming\nUnattributed (ARCH-1)\n"
+	if result != expected {
+		t.Errorf("renderADRTemplate() = %q, want %q", result, expected)
+	}
+	if _, ok := vars["author"]; ok {
+		t.Error("renderADRTemplate() modified the vars it was given")
+	}
+
+	if _, err := renderADRTemplate("{{.Owner}}", vars); err == nil {
+		t.Error("renderADRTemplate() should fail on a key with no value")
+	}
+}
+
+func TestRenderADRTemplateCodeExample(t *testing.T) {
+	originalEngine := templateEngine
+	templateEngine = ""
+	defer func() { templateEngine = originalEngine }()
+
+	template := "# ADR {{number}}: {{title}}\n\nDeploy with:\n\n```yaml\nimage: {{ .Values.image }}\n```\n"
+	result, err := renderADRTemplate(template, map[string]string{"number": "007", "title": "Use Helm"})
+	if err != nil {
+		t.Fatalf("renderADRTemplate() failed: %v", err)
+	}
+	expected := "# ADR 007: Use Helm\n\nDeploy with:\n\n```yaml\nimage: {{ .Values.image }}\n```\n"
+	if result != expected {
+		t.Errorf("renderADRTemplate() = %q, want %q", result, expected)
+	}
+}
+
+func TestNewADRContentGoTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalEngine := adrDir, templateEngine
+	adrDir, templateEngine = tempDir, ""
+	defer func() { adrDir, templateEngine = originalAdrDir, originalEngine }()
+
+	template := "# ADR {{.Number}}: {{.Title}}\n\n**Status**: {{.Status}}  \n**Date**: {{.Date}}\n{{with .Tags}}\nTags: {{range .}}#{{.}} {{end}}{{end}}\n"
+	if err := writeFile(filepath.Join(tempDir, templateFile), template); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	content, err := newADRContent("003", "Accepted", "Use Go", "2024-01-01", createOptions{tags: []string{"lang", "backend"}})
+	if err != nil {
+		t.Fatalf("newADRContent() failed: %v", err)
+	}
+	expected := "# ADR 003: Use Go\n\n**Status**: Accepted  \n**Date**: 2024-01-01\n\nTags: #lang #backend \n"
+	if content != expected {
+		t.Errorf("newADRContent() = %q, want %q", content, expected)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// exportTargets maps --target names to the functions writing them into an
+// output directory.
+var exportTargets = map[string]func(adrs []ADR, outDir string) error{
+	"log4brains":  exportLog4brains,
+	"csv":         exportCSV,
+	"single-file": exportSingleFile,
+	"prometheus":  exportPrometheus,
+}
+
+func exportTargetNames() []string {
+	names := make([]string, 0, len(exportTargets))
+	for name := range exportTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// log4brainsStatus maps a status onto the values log4brains understands.
+// Unknown statuses become drafts.
+func log4brainsStatus(status string) (string, bool) {
+	if known, ok := normalizeStatus(status); ok {
+		return strings.ToLower(known), true
+	}
+	return "draft", false
+}
+
+// log4brainsFilenames assigns every ADR its log4brains name, the
+// YYYYMMDD-slug.md form the tool derives its slugs from. The date falls back
+// to the file's modification time; clashes get the ADR number appended.
+func log4brainsFilenames(adrs []ADR) map[string]string {
+	names := make(map[string]string)
+	used := make(map[string]bool)
+	for _, adr := range adrs {
+		date, err := parseDate(adr.Date)
+		if err != nil {
+			date = time.Now()
+			if info, err := fsys.Stat(filepath.Join(adrDir, adr.Filename)); err == nil {
+				date = info.ModTime()
+			}
+		}
+
+		slug := toKebabCase(adr.Title)
+		if separator != "-" {
+			slug = strings.ReplaceAll(slug, separator, "-")
+		}
+		name := date.Format("20060102") + "-" + slug
+		if used[name] && adr.Number != "" {
+			name += "-" + adr.Number
+		}
+		used[name] = true
+		names[adr.Filename] = name + ".md"
+	}
+	return names
+}
+
+// toLog4brains rewrites an ADR into the log4brains layout: a plain "# Title"
+// heading followed by "- Status:" and "- Date:" list items, with links to
+// other ADRs pointing at their new filenames.
+// A superseded ADR names its successor, when known, the way log4brains links it.
+func toLog4brains(adr ADR, content string, names map[string]string, successor string) string {
+	lines := strings.Split(content, "\n")
+	for _, name := range []string{"Status", "Previous Status", "Date"} {
+		lines = removeFields(lines, name)
+	}
+
+	title := getCurrentTitle(content)
+	if title == "" {
+		title = adr.Title
+	}
+	status, _ := log4brainsStatus(adr.Status)
+	if status == "superseded" && successor != "" {
+		slug := strings.TrimSuffix(successor, ".md")
+		status = fmt.Sprintf("superseded by [%s](%s)", slug, successor)
+	}
+	date := names[adr.Filename][:8]
+	header := []string{
+		"# " + title,
+		"",
+		"- Status: " + status,
+		"- Date: " + date[:4] + "-" + date[4:6] + "-" + date[6:],
+		"",
+	}
+
+	headingAt := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "# ") {
+			headingAt = i
+			break
+		}
+	}
+	rest := lines
+	if headingAt >= 0 {
+		rest = lines[headingAt+1:]
+		header = append(append([]string{}, lines[:headingAt]...), header...)
+	}
+	for len(rest) > 0 && strings.TrimSpace(rest[0]) == "" {
+		rest = rest[1:]
+	}
+
+	pairs := make([]string, 0, len(names)*2)
+	for oldName, newName := range names {
+		pairs = append(pairs, oldName, newName)
+	}
+	return strings.NewReplacer(pairs...).Replace(strings.Join(append(header, rest...), "\n"))
+}
+
+func exportLog4brains(adrs []ADR, outDir string) error {
+	adrFolder := filepath.Join(outDir, "docs", "adr")
+	if same, _ := sameDir(adrFolder, adrDir); same {
+		return fmt.Errorf("refusing to export into the source directory %s", adrDir)
+	}
+	if err := ensureDir(adrFolder); err != nil {
+		return err
+	}
+
+	name := filepath.Base(outDir)
+	if abs, err := filepath.Abs(outDir); err == nil {
+		name = filepath.Base(abs)
+	}
+	manifest := fmt.Sprintf("---\nproject:\n  name: %q\n  tz: UTC\n  adrFolder: ./docs/adr\n", name)
+	if err := writeFile(filepath.Join(outDir, ".log4brains.yml"), manifest); err != nil {
+		return err
+	}
+
+	names := log4brainsFilenames(adrs)
+	byNumber := make(map[string]string)
+	for _, adr := range adrs {
+		if adr.Number != "" {
+			byNumber[adr.Number] = names[adr.Filename]
+		}
+	}
+
+	for _, adr := range adrs {
+		successor := ""
+		for _, relation := range adr.Relations {
+			if strings.EqualFold(relation.Type, "Superseded by") || strings.EqualFold(relation.Type, "Replaced by") {
+				successor = byNumber[relation.TargetNumber]
+			}
+		}
+
+		if _, ok := log4brainsStatus(adr.Status); !ok {
+			fmt.Fprintf(os.Stderr, "Warning: %s has unknown status %q, exported as draft\n", adr.Filename, adr.Status)
+		}
+		content, err := readFileWithRetry(filepath.Join(adrDir, adr.Filename))
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(adrFolder, names[adr.Filename]), toLog4brains(adr, string(content), names, successor)); err != nil {
+			return err
+		}
+	}
+
+	index := "<!-- This file is shown as the home page of the log4brains knowledge base -->\n\n# Architecture Decision Records\n\nExported from adrgen.\n"
+	return writeFile(filepath.Join(adrFolder, "index.md"), index)
+}
+
+func sameDir(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(infoA, infoB), nil
+}
+
+func runExportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	registerDirFlags(fs)
+	target := fs.String("target", "", "Export format: "+strings.Join(exportTargetNames(), ", "))
+	fs.StringVar(target, "format", "", "Alias for --target")
+	out := fs.String("out", "", "Directory to write the export to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+
+	export, ok := exportTargets[*target]
+	if !ok {
+		return fmt.Errorf("invalid --target %q (supported: %s)", *target, strings.Join(exportTargetNames(), ", "))
+	}
+	if *out == "" {
+		return fmt.Errorf("--out is required")
+	}
+
+	adrs, err := loadADRs()
+	if err != nil {
+		return err
+	}
+	if err := export(adrs, *out); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Exported %d ADR(s) to %s\n", len(adrs), *out)
+	return nil
+}
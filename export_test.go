@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLog4brainsStatus(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		known    bool
+	}{
+		{"Accepted", "accepted", true},
+		{"superseded", "superseded", true},
+		{"Draft", "draft", false},
+		{"", "draft", false},
+	}
+
+	for _, test := range tests {
+		result, known := log4brainsStatus(test.input)
+		if result != test.expected || known != test.known {
+			t.Errorf("log4brainsStatus(%q) = %q, %v, want %q, %v", test.input, result, known, test.expected, test.known)
+		}
+	}
+}
+
+func TestExportLog4brains(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = filepath.Join(tempDir, "project", "docs", "adr")
+	defer func() { adrDir = originalAdrDir }()
+	if err := ensureDir(adrDir); err != nil {
+		t.Fatalf("Failed to create ADR directory: %v", err)
+	}
+
+	testFiles := map[string]string{
+		"adr-001-use-rabbitmq.md": "# ADR 001: Use RabbitMQ\n\n**Status**: Superseded  \n**Previous Status**: Accepted  \n**Date**: 2023-02-01\n\n## Context\n\nQueues.\n\n## Relations\n\n- Replaced by: [ADR 002](adr-002-use-kafka.md)\n",
+		"adr-002-use-kafka.md":    "# ADR 002: Use Kafka\n\n**Status**: Accepted  \n**Date**: 2024-03-15\n\n## Relations\n\n- Replaces: [ADR 001](adr-001-use-rabbitmq.md)\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(adrDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	adrs, err := loadADRs()
+	if err != nil {
+		t.Fatalf("loadADRs() failed: %v", err)
+	}
+	outDir := filepath.Join(tempDir, "out")
+	if err := exportLog4brains(adrs, outDir); err != nil {
+		t.Fatalf("exportLog4brains() failed: %v", err)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(outDir, ".log4brains.yml"))
+	if err != nil || !strings.Contains(string(manifest), "adrFolder: ./docs/adr") {
+		t.Errorf("Manifest = %q (%v), want an adrFolder entry", manifest, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outDir, "docs", "adr", "20230201-use-rabbitmq.md"))
+	if err != nil {
+		t.Fatalf("Failed to read exported ADR: %v", err)
+	}
+	expected := "# Use RabbitMQ\n\n- Status: superseded by [20240315-use-kafka](20240315-use-kafka.md)\n- Date: 2023-02-01\n\n## Context\n\nQueues.\n\n## Relations\n\n- Replaced by: [ADR 002](20240315-use-kafka.md)\n"
+	if string(content) != expected {
+		t.Errorf("Exported ADR = %q, want %q", content, expected)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "docs", "adr", "index.md")); err != nil {
+		t.Errorf("Expected an index.md in the export: %v", err)
+	}
+
+	if err := exportLog4brains(adrs, filepath.Join(tempDir, "project")); err == nil {
+		t.Error("Expected exporting into the source directory to fail")
+	}
+}
+
+func TestExportSingleFile(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = filepath.Join(tempDir, "adr")
+	defer func() { adrDir = originalAdrDir }()
+	if err := ensureDir(adrDir); err != nil {
+		t.Fatalf("Failed to create ADR directory: %v", err)
+	}
+
+	testFiles := map[string]string{
+		"adr-010-use-grpc.md":   "# ADR 010: Use gRPC (v2)!\n\n## Context\n\nSee [ADR 002](adr-002-use-kafka.md).\n",
+		"adr-002-use-kafka.md":  "# ADR 002: Use Kafka\n\n## Context\n\nQueues.\n",
+		"adr-003-no-heading.md": "**Status**: Proposed  \n",
+		"adr-004-use-kafka.md":  "# ADR 002: Use Kafka\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(adrDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	adrs, err := loadADRs()
+	if err != nil {
+		t.Fatalf("loadADRs() failed: %v", err)
+	}
+	outDir := filepath.Join(tempDir, "out")
+	if err := exportSingleFile(adrs, outDir); err != nil {
+		t.Fatalf("exportSingleFile() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outDir, singleFile))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", singleFile, err)
+	}
+	expected := "# Architecture Decision Records\n\n## Contents\n\n" +
+		"- [ADR 002: Use Kafka](#adr-002-use-kafka)\n" +
+		"- [ADR 003: No Heading](#adr-003-no-heading)\n" +
+		"- [ADR 002: Use Kafka](#adr-002-use-kafka-1)\n" +
+		"- [ADR 010: Use gRPC (v2)!](#adr-010-use-grpc-v2)\n" +
+		"\n---\n\n# ADR 002: Use Kafka\n\n## Context\n\nQueues.\n" +
+		"\n---\n\n# ADR 003: No Heading\n\n**Status**: Proposed\n" +
+		"\n---\n\n# ADR 002: Use Kafka\n" +
+		"\n---\n\n# ADR 010: Use gRPC (v2)!\n\n## Context\n\nSee [ADR 002](#adr-002-use-kafka).\n"
+	if string(content) != expected {
+		t.Errorf("%s =\n%s\nwant:\n%s", singleFile, content, expected)
+	}
+
+	if err := exportSingleFile(adrs, adrDir); err == nil {
+		t.Error("exportSingleFile() into the ADR directory should fail")
+	}
+}
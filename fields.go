@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Metadata fields such as Status can be written in several styles. Updates
+// keep whatever style the file already uses.
+type fieldStyle int
+
+const (
+	fieldBold    fieldStyle = iota // **Status**: Accepted
+	fieldPlain                     // Status: Accepted
+	fieldSection                   // ## Status, value on the next non-empty line
+)
+
+type field struct {
+	Style fieldStyle
+	Line  int // line of the field, or of the heading for sections
+	End   int // last line belonging to the field
+	Value string
+}
+
+func parseFieldLine(line, name string) (fieldStyle, string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if value, ok := cutPrefixFold(trimmed, "**"+name+"**:"); ok {
+		return fieldBold, strings.TrimSpace(value), true
+	}
+	if value, ok := cutPrefixFold(trimmed, name+":"); ok {
+		return fieldPlain, strings.TrimSpace(value), true
+	}
+	if heading, ok := strings.CutPrefix(trimmed, "## "); ok && strings.EqualFold(strings.TrimSpace(heading), name) {
+		return fieldSection, "", true
+	}
+	return 0, "", false
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// findFields returns every occurrence of the named field.
+func findFields(lines []string, name string) []field {
+	var fields []field
+	for i := 0; i < len(lines); i++ {
+		style, value, ok := parseFieldLine(lines[i], name)
+		if !ok {
+			continue
+		}
+		f := field{Style: style, Line: i, End: i, Value: value}
+		if style == fieldSection {
+			// The value is the first non-empty line before the next heading
+			for j := i + 1; j < len(lines); j++ {
+				trimmed := strings.TrimSpace(lines[j])
+				if trimmed == "" {
+					continue
+				}
+				if !strings.HasPrefix(trimmed, "#") {
+					f.Value, f.End = trimmed, j
+				}
+				break
+			}
+		}
+		fields = append(fields, f)
+		i = f.End
+	}
+	return fields
+}
+
+// metadataSections are the fields that may be written as "## Name" sections
+// in the header of an ADR.
+var metadataSections = []string{"Title", "Status", "Previous Status", "Date", "Timestamp"}
+
+// headerEnd returns the index of the first "##" heading that opens a body
+// section, i.e. one that isn't a section-style metadata field. Everything
+// before it is the header region.
+func headerEnd(lines []string) int {
+	for i, line := range lines {
+		heading, ok := strings.CutPrefix(strings.TrimSpace(line), "## ")
+		if !ok {
+			continue
+		}
+		if !slices.ContainsFunc(metadataSections, func(name string) bool { return strings.EqualFold(strings.TrimSpace(heading), name) }) {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// headerField returns the first occurrence of the named field in the header
+// region.
+func headerField(lines []string, name string) (field, bool) {
+	end := headerEnd(lines)
+	for _, f := range findFields(lines, name) {
+		if f.Line < end {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+func findField(lines []string, name string) (field, bool) {
+	fields := findFields(lines, name)
+	if len(fields) == 0 {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+func formatField(style fieldStyle, name, value string) []string {
+	switch style {
+	case fieldPlain:
+		return []string{fmt.Sprintf("%s: %s  ", name, value)}
+	case fieldSection:
+		return []string{"## " + name, "", value}
+	default:
+		return []string{fmt.Sprintf("**%s**: %s  ", name, value)}
+	}
+}
+
+// replaceLines swaps lines[start:end+1] for replacement.
+func replaceLines(lines []string, start, end int, replacement []string) []string {
+	result := make([]string, 0, len(lines)-(end-start+1)+len(replacement))
+	result = append(result, lines[:start]...)
+	result = append(result, replacement...)
+	return append(result, lines[end+1:]...)
+}
+
+// removeFields drops every occurrence of the named field. Section fields also
+// lose the blank line that followed them.
+func removeFields(lines []string, name string) []string {
+	fields := findFields(lines, name)
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		end := f.End
+		if f.Style == fieldSection && end+1 < len(lines) && strings.TrimSpace(lines[end+1]) == "" {
+			end++
+		}
+		lines = replaceLines(lines, f.Line, end, nil)
+	}
+	return lines
+}
+
+// canonicalizeFields rewrites legacy Title, Status, Previous Status and Date
+// fields in the bold **Field**: style, and turns a Title field into the
+// "# ADR NNN: Title" heading when the file has none.
+func canonicalizeFields(content, number string) string {
+	lines := strings.Split(content, "\n")
+
+	if title, ok := findField(lines, "Title"); ok && title.Style != fieldSection {
+		if getHeadingLine(content) == "" {
+			lines[title.Line] = fmt.Sprintf("# ADR %s: %s", number, title.Value)
+		} else {
+			lines = replaceLines(lines, title.Line, title.End, nil)
+		}
+	}
+
+	for _, name := range []string{"Status", "Previous Status", "Date"} {
+		f, ok := findField(lines, name)
+		if !ok || f.Style == fieldBold {
+			continue
+		}
+		lines = replaceLines(lines, f.Line, f.End, formatField(fieldBold, name, f.Value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// insertFields adds field lines after the Date field, or the Status field
+// when there is no date. Content without either is returned unchanged.
+func insertFields(content string, extra []string) string {
+	lines := strings.Split(content, "\n")
+	anchor, ok := findField(lines, "Date")
+	if !ok {
+		anchor, ok = findField(lines, "Status")
+	}
+	if !ok || anchor.Style == fieldSection || len(extra) == 0 {
+		return content
+	}
+	// Markdown line breaks between the fields, none after the last one
+	extra = append([]string{strings.TrimRight(lines[anchor.End], " ") + "  "}, extra...)
+	extra[len(extra)-1] = strings.TrimRight(extra[len(extra)-1], " ")
+	return strings.Join(replaceLines(lines, anchor.End, anchor.End, extra), "\n")
+}
+
+// setField replaces the value of the named field in its existing style, or
+// inserts it with insertFields when the file has none and value is set.
+func setField(content, name, value string) string {
+	lines := strings.Split(content, "\n")
+	f, ok := findField(lines, name)
+	if !ok {
+		if value == "" {
+			return content
+		}
+		return insertFields(content, formatField(fieldBold, name, value))
+	}
+	if f.Value == value {
+		return content
+	}
+	return strings.Join(replaceLines(lines, f.Line, f.End, formatField(f.Style, name, value)), "\n")
+}
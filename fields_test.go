@@ -0,0 +1,142 @@
+package main
+
+import "testing"
+
+func TestGetCurrentStatusVariants(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"# ADR 001: Bold\n\n**Status**: Accepted  \n", "Accepted"},
+		{"Title: Plain\nStatus: Proposed\n", "Proposed"},
+		{"# Use Kafka\n\n## Status\n\nDeprecated\n\n## Context\n", "Deprecated"},
+		{"# ADR 001: None\n\n## Context\n", ""},
+	}
+
+	for _, test := range tests {
+		if result := getCurrentStatus(test.content); result != test.expected {
+			t.Errorf("getCurrentStatus(%q) = %q, want %q", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestUpdateStatusKeepsStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "bold",
+			content:  "# ADR 001: Bold\n\n**Status**: Proposed  \n**Date**: 2024-01-01\n",
+			expected: "# ADR 001: Bold\n\n**Status**: Accepted  \n**Previous Status**: Proposed  \n**Date**: 2024-01-01\n",
+		},
+		{
+			name:     "plain",
+			content:  "Title: Plain\nStatus: Proposed\nPrevious Status: Draft\nDate: 2024-01-01\n",
+			expected: "Title: Plain\nStatus: Accepted  \nPrevious Status: Proposed  \nDate: 2024-01-01\n",
+		},
+		{
+			name:     "section",
+			content:  "# Use Kafka\n\n## Status\n\nProposed\n\n## Context\n",
+			expected: "# Use Kafka\n\n## Status\n\nAccepted\n\n## Previous Status\n\nProposed\n\n## Context\n",
+		},
+	}
+
+	for _, test := range tests {
+		if result := updateStatus(test.content, "Accepted"); result != test.expected {
+			t.Errorf("updateStatus(%s) = %q, want %q", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestLegacyTitle(t *testing.T) {
+	content := "Title: Use Kafka\nStatus: Proposed\n"
+	if result := getCurrentTitle(content); result != "Use Kafka" {
+		t.Errorf("getCurrentTitle() = %q, want %q", result, "Use Kafka")
+	}
+
+	expected := "Title: Use Pulsar\nStatus: Proposed\n"
+	if result := updateTitle(content, "001", "Use Pulsar"); result != expected {
+		t.Errorf("updateTitle() = %q, want %q", result, expected)
+	}
+}
+
+func TestUpdateTitleInsertsHeading(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"no heading",
+			"\n**Status**: Proposed  \n\n## Context\n",
+			"# ADR 004: Use Kafka\n\n**Status**: Proposed  \n\n## Context\n",
+		},
+		{
+			"frontmatter",
+			"---\ntags: [messaging]\n---\n**Status**: Proposed  \n",
+			"---\ntags: [messaging]\n---\n\n# ADR 004: Use Kafka\n\n**Status**: Proposed  \n",
+		},
+	}
+	for _, test := range tests {
+		result := updateTitle(test.content, "004", "Use Kafka")
+		if result != test.expected {
+			t.Errorf("updateTitle(%s) = %q, want %q", test.name, result, test.expected)
+		}
+		if title := getCurrentTitle(result); title != "Use Kafka" {
+			t.Errorf("getCurrentTitle(%s) after update = %q, want %q", test.name, title, "Use Kafka")
+		}
+		if again := updateTitle(result, "004", "Use Kafka"); again != result {
+			t.Errorf("updateTitle(%s) is not idempotent: %q", test.name, again)
+		}
+	}
+}
+
+func TestCanonicalizeFields(t *testing.T) {
+	content := "Title: Use Kafka\nStatus: Proposed\nDate: 2024-01-01\n\n## Context\n"
+	expected := "# ADR 004: Use Kafka\n**Status**: Proposed  \n**Date**: 2024-01-01  \n\n## Context\n"
+	if result := canonicalizeFields(content, "004"); result != expected {
+		t.Errorf("canonicalizeFields() = %q, want %q", result, expected)
+	}
+
+	section := "# ADR 004: Use Kafka\n\n## Status\n\nAccepted\n\n## Context\n"
+	expected = "# ADR 004: Use Kafka\n\n**Status**: Accepted  \n\n## Context\n"
+	if result := canonicalizeFields(section, "004"); result != expected {
+		t.Errorf("canonicalizeFields() = %q, want %q", result, expected)
+	}
+}
+
+func TestUpdateStatusNoPreviousStatus(t *testing.T) {
+	original := noPreviousStatus
+	noPreviousStatus = true
+	defer func() { noPreviousStatus = original }()
+
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{
+			"# ADR 001: Bold\n\n**Status**: Proposed  \n**Date**: 2024-01-01\n",
+			"# ADR 001: Bold\n\n**Status**: Accepted  \n**Date**: 2024-01-01\n",
+		},
+		{
+			"# Use Kafka\n\n## Status\n\nProposed\n\n## Context\n",
+			"# Use Kafka\n\n## Status\n\nAccepted\n\n## Context\n",
+		},
+		{
+			"# ADR 001: No Status\n\n## Context\n",
+			"# ADR 001: No Status\n\n**Status**: Accepted\n\n## Context\n",
+		},
+	}
+
+	for _, test := range tests {
+		result := updateStatus(test.content, "Accepted")
+		if result != test.expected {
+			t.Errorf("updateStatus(%q) = %q, want %q", test.content, result, test.expected)
+		}
+		if status := getCurrentStatus(result); status != "Accepted" {
+			t.Errorf("getCurrentStatus() = %q after update, want %q", status, "Accepted")
+		}
+	}
+}
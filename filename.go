@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var filenamePlaceholderPattern = regexp.MustCompile(`\{\{(\w+)\}\}`)
+
+var filenamePlaceholderPatterns = map[string]string{
+	"number":   `\d+`,
+	"slug":     `.+`,
+	"date":     `\d{4}-\d{2}-\d{2}`,
+	"year":     `\d{4}`,
+	"type":     `[^/\\]+?`,
+	"category": `[A-Za-z][A-Za-z0-9]*`,
+}
+
+var (
+	compiledFilenamePatterns   = make(map[string]*regexp.Regexp)
+	compiledFilenamePatternsMu sync.Mutex
+)
+
+var categoryPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+func activeFilenameTemplate() string {
+	if filenameTemplate != "" {
+		return filenameTemplate
+	}
+	if category != "" {
+		return "adr" + separator + "{{category}}" + separator + "{{number}}" + separator + "{{slug}}.md"
+	}
+	return "adr" + separator + "{{number}}" + separator + "{{slug}}.md"
+}
+
+func validateFilenameTemplate(template string) error {
+	if !strings.Contains(template, "{{number}}") {
+		return fmt.Errorf("filename template must contain {{number}}")
+	}
+	if !strings.HasSuffix(template, ".md") {
+		return fmt.Errorf("filename template must end with .md")
+	}
+	if strings.ContainsAny(template, `/\`) {
+		return fmt.Errorf("filename template must not contain path separators")
+	}
+	for _, match := range filenamePlaceholderPattern.FindAllStringSubmatch(template, -1) {
+		if _, ok := filenamePlaceholderPatterns[match[1]]; !ok {
+			return fmt.Errorf("unknown filename placeholder {{%s}}", match[1])
+		}
+	}
+	if category != "" && !strings.Contains(template, "{{category}}") {
+		return fmt.Errorf("filename template must contain {{category}} when a category is set")
+	}
+	return nil
+}
+
+// filenamePattern turns the filename template into a regular expression with
+// a named group for each placeholder, anchored on {{number}}.
+func filenamePattern() *regexp.Regexp {
+	return compileFilenamePattern(activeFilenameTemplate())
+}
+
+func compileFilenamePattern(template string) *regexp.Regexp {
+	compiledFilenamePatternsMu.Lock()
+	defer compiledFilenamePatternsMu.Unlock()
+	key := template + "\x00" + numberFormat
+	if pattern, ok := compiledFilenamePatterns[key]; ok {
+		return pattern
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	seen := make(map[string]bool)
+	last := 0
+	for _, loc := range filenamePlaceholderPattern.FindAllStringSubmatchIndex(template, -1) {
+		expr.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		name := template[loc[2]:loc[3]]
+		placeholder, ok := filenamePlaceholderPatterns[name]
+		if !ok {
+			placeholder = `.+?`
+		}
+		if name == "number" {
+			placeholder = activeNumberFormatter().Pattern()
+		}
+		if seen[name] {
+			fmt.Fprintf(&expr, "(?:%s)", placeholder)
+		} else {
+			fmt.Fprintf(&expr, "(?P<%s>%s)", name, placeholder)
+			seen[name] = true
+		}
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	expr.WriteString("$")
+
+	pattern := regexp.MustCompile(expr.String())
+	compiledFilenamePatterns[key] = pattern
+	return pattern
+}
+
+func matchFilenameFields(filename string) map[string]string {
+	pattern := filenamePattern()
+	match := pattern.FindStringSubmatch(filename)
+	if match == nil {
+		return nil
+	}
+
+	fields := make(map[string]string)
+	for i, name := range pattern.SubexpNames() {
+		if name != "" {
+			fields[name] = match[i]
+		}
+	}
+	return fields
+}
+
+// caseCollision returns the file among files, other than except, whose name
+// differs from name only by case. The two would be one file on macOS and
+// Windows.
+func caseCollision(files []os.DirEntry, name, except string) (string, bool) {
+	for _, file := range files {
+		other := file.Name()
+		if other != name && other != except && strings.EqualFold(other, name) {
+			return other, true
+		}
+	}
+	return "", false
+}
+
+// foldsCase reports whether name, which differs from existing only by case,
+// opens existing in dir: the directory is on a case-insensitive filesystem,
+// as by default on macOS and Windows.
+func foldsCase(dir string, files []os.DirEntry, name, existing string) bool {
+	if name == existing || !strings.EqualFold(name, existing) {
+		return false
+	}
+	for _, file := range files {
+		if file.Name() == name {
+			return false
+		}
+	}
+	_, err := fsys.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+func findADRFile(files []os.DirEntry, number string) (string, bool) {
+	want, ok := parseNumber(number)
+	if !ok {
+		return findCategorizedADRFile(files, number)
+	}
+	for _, file := range files {
+		if file.IsDir() || !isADRFile(file.Name()) {
+			continue
+		}
+		if num, ok := matchADRFilename(file.Name()); ok && num == want {
+			return file.Name(), true
+		}
+	}
+	return "", false
+}
+
+// categorizedADR splits a filename such as adr-SEC-001-rotate-keys.md into
+// its category, number and slug. Without --category, ADRs in a category are
+// outside the main number sequence, but commands over the whole repository
+// still read them, numbered like SEC-001.
+func categorizedADR(filename string) (string, int, string, bool) {
+	if category != "" || filenameTemplate != "" {
+		return "", 0, "", false
+	}
+	pattern := compileFilenamePattern("adr" + separator + "{{category}}" + separator + "{{number}}" + separator + "{{slug}}.md")
+	match := pattern.FindStringSubmatch(filename)
+	if match == nil {
+		return "", 0, "", false
+	}
+	num, ok := parseNumber(match[pattern.SubexpIndex("number")])
+	if !ok {
+		return "", 0, "", false
+	}
+	return match[pattern.SubexpIndex("category")], num, match[pattern.SubexpIndex("slug")], true
+}
+
+// qualifiedNumber is the number of an ADR in a category, such as SEC-001.
+func qualifiedNumber(cat string, num int) string {
+	return strings.ToUpper(cat) + "-" + formatNumber(num)
+}
+
+// parseQualifiedNumber splits a number such as SEC-001 into its category and
+// number.
+func parseQualifiedNumber(number string) (string, int, bool) {
+	cat, rest, ok := strings.Cut(number, "-")
+	if !ok || !categoryPattern.MatchString(cat) {
+		return "", 0, false
+	}
+	num, ok := parseNumber(rest)
+	return cat, num, ok
+}
+
+// findCategorizedADRFile finds the ADR with a qualified number such as
+// SEC-001 among files.
+func findCategorizedADRFile(files []os.DirEntry, number string) (string, bool) {
+	wantCategory, want, ok := parseQualifiedNumber(number)
+	if !ok {
+		return "", false
+	}
+	for _, file := range files {
+		if file.IsDir() || !isADRFile(file.Name()) {
+			continue
+		}
+		if cat, num, _, ok := categorizedADR(file.Name()); ok && num == want && strings.EqualFold(cat, wantCategory) {
+			return file.Name(), true
+		}
+	}
+	return "", false
+}
+
+func matchADRFilename(filename string) (int, bool) {
+	fields := matchFilenameFields(filename)
+	if fields == nil {
+		return 0, false
+	}
+	// With a category, numbering and lookups only see that category's ADRs
+	if category != "" && !strings.EqualFold(fields["category"], category) {
+		return 0, false
+	}
+	return parseNumber(fields["number"])
+}
+
+// renderFilename builds the filename for an ADR. When renaming, the date,
+// year and type fields of the existing filename are kept.
+func renderFilename(number, title, existing string) string {
+	now := time.Now()
+	values := map[string]string{
+		"number":   number,
+		"slug":     toKebabCase(title),
+		"date":     now.Format("2006-01-02"),
+		"year":     now.Format("2006"),
+		"type":     adrType,
+		"category": category,
+	}
+	if existing != "" {
+		for name, value := range matchFilenameFields(existing) {
+			if name != "number" && name != "slug" && name != "category" && value != "" {
+				values[name] = value
+			}
+		}
+	}
+
+	return filenamePlaceholderPattern.ReplaceAllStringFunc(activeFilenameTemplate(), func(placeholder string) string {
+		return values[strings.Trim(placeholder, "{}")]
+	})
+}
+
+func adrFilename(number, title string) string {
+	return renderFilename(number, title, "")
+}
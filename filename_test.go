@@ -0,0 +1,169 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestRenderFilename(t *testing.T) {
+	originalTemplate := filenameTemplate
+	defer func() { filenameTemplate = originalTemplate }()
+
+	filenameTemplate = ""
+	if result := adrFilename("007", "Use Redis"); result != "adr-007-use-redis.md" {
+		t.Errorf("adrFilename() = %q, want %q", result, "adr-007-use-redis.md")
+	}
+
+	filenameTemplate = "{{year}}-{{number}}-{{slug}}.md"
+	expected := time.Now().Format("2006") + "-007-use-redis.md"
+	if result := adrFilename("007", "Use Redis"); result != expected {
+		t.Errorf("adrFilename() = %q, want %q", result, expected)
+	}
+
+	// Renames keep the date fields of the existing file
+	filenameTemplate = "{{date}}-{{number}}-{{slug}}.md"
+	result := renderFilename("007", "Use Valkey", "2019-03-04-007-use-redis.md")
+	if result != "2019-03-04-007-use-valkey.md" {
+		t.Errorf("renderFilename() = %q, want %q", result, "2019-03-04-007-use-valkey.md")
+	}
+}
+
+func TestFilenameTemplateParsing(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalTemplate := adrDir, filenameTemplate
+	adrDir, filenameTemplate = tempDir, "{{year}}-{{number}}-{{slug}}.md"
+	defer func() { adrDir, filenameTemplate = originalAdrDir, originalTemplate }()
+
+	for _, file := range []string{"2023-001-first-choice.md", "2024-012-use-redis.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if num, ok := parseADRNumber("2024-012-use-redis.md"); !ok || num != 12 {
+		t.Errorf("parseADRNumber() = %d, %v, want 12, true", num, ok)
+	}
+	if result := extractTitleFromFilename("2024-012-use-redis.md"); result != "Use Redis" {
+		t.Errorf("extractTitleFromFilename() = %q, want %q", result, "Use Redis")
+	}
+	if !adrExists("012") {
+		t.Error("adrExists() returned false for an ADR matching the filename template")
+	}
+	if adrExists("2024") {
+		t.Error("adrExists() matched the year field instead of the number")
+	}
+	if result := getNextADRNumber(); result != "013" {
+		t.Errorf("getNextADRNumber() = %q, want %q", result, "013")
+	}
+}
+
+func TestValidateFilenameTemplate(t *testing.T) {
+	valid := []string{"adr-{{number}}-{{slug}}.md", "{{date}}_{{number}}_{{type}}_{{slug}}.md"}
+	for _, template := range valid {
+		if err := validateFilenameTemplate(template); err != nil {
+			t.Errorf("validateFilenameTemplate(%q) failed: %v", template, err)
+		}
+	}
+
+	invalid := []string{"adr-{{slug}}.md", "adr-{{number}}-{{slug}}.txt", "{{number}}/{{slug}}.md", "{{number}}-{{author}}.md"}
+	for _, template := range invalid {
+		if err := validateFilenameTemplate(template); err == nil {
+			t.Errorf("validateFilenameTemplate(%q) should have failed", template)
+		}
+	}
+}
+
+func TestFilenamePatternEscapesLiterals(t *testing.T) {
+	originalTemplate := filenameTemplate
+	filenameTemplate = "adr.{{number}}.{{slug}}.md"
+	defer func() { filenameTemplate = originalTemplate }()
+
+	if filenamePattern().MatchString("adrX001Xslug.md") {
+		t.Error("filename pattern should treat literal dots literally")
+	}
+	if !regexp.MustCompile(`\(\?P<number>`).MatchString(filenamePattern().String()) {
+		t.Error("filename pattern should capture the number field")
+	}
+}
+
+func TestCategoryNumbering(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalTemplate, originalCategory := adrDir, filenameTemplate, category
+	adrDir, filenameTemplate, category = tempDir, "", "SEC"
+	defer func() { adrDir, filenameTemplate, category = originalAdrDir, originalTemplate, originalCategory }()
+
+	for _, file := range []string{"adr-007-use-redis.md", "adr-SEC-001-rotate-keys.md", "adr-PERF-004-cache-reads.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if result := adrFilename("002", "Use Vault"); result != "adr-SEC-002-use-vault.md" {
+		t.Errorf("adrFilename() = %q, want %q", result, "adr-SEC-002-use-vault.md")
+	}
+	if result := getNextADRNumber(); result != "002" {
+		t.Errorf("getNextADRNumber() = %q, want %q", result, "002")
+	}
+	if !adrExists("001") {
+		t.Error("adrExists() returned false for an ADR in the category")
+	}
+	if adrExists("004") || adrExists("007") {
+		t.Error("adrExists() matched an ADR outside the category")
+	}
+
+	category = "sec"
+	if !adrExists("001") {
+		t.Error("category matching should ignore case")
+	}
+
+	filenameTemplate = "{{number}}-{{slug}}.md"
+	if err := validateFilenameTemplate(filenameTemplate); err == nil {
+		t.Error("validateFilenameTemplate() should require {{category}} when a category is set")
+	}
+}
+
+func TestListMixedCategories(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalTemplate, originalCategory := adrDir, filenameTemplate, category
+	adrDir, filenameTemplate, category = tempDir, "", ""
+	defer func() { adrDir, filenameTemplate, category = originalAdrDir, originalTemplate, originalCategory }()
+
+	files := map[string]string{
+		"adr-001-use-go.md":           "# ADR 001: Use Go\n\n**Status**: Accepted\n",
+		"adr-SEC-001-rotate-keys.md":  "# ADR SEC-001: Rotate Keys\n\n**Status**: Proposed\n",
+		"adr-PERF-002-cache-reads.md": "# ADR PERF-002: Cache Reads\n\n**Status**: Accepted\n",
+	}
+	for name, content := range files {
+		if err := writeFile(filepath.Join(tempDir, name), content); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	var err error
+	stdout, _ := captureOutput(t, func() { err = runListCommand(nil) })
+	if err != nil {
+		t.Fatalf("runListCommand() failed: %v", err)
+	}
+	if expected := "001       Use Go       Accepted\nPERF-002  Cache Reads  Accepted\nSEC-001   Rotate Keys  Proposed\n"; stdout != expected {
+		t.Errorf("runListCommand() = %q, want %q", stdout, expected)
+	}
+	if result := getNextADRNumber(); result != "002" {
+		t.Errorf("getNextADRNumber() = %q, want %q: categories have their own sequences", result, "002")
+	}
+
+	captureOutput(t, func() { err = runRelateCommand([]string{"--from", "sec-001", "--to", "001", "--bidirectional"}) })
+	if err != nil {
+		t.Fatalf("runRelateCommand() failed: %v", err)
+	}
+	adrs, err := loadADRs()
+	if err != nil {
+		t.Fatalf("loadADRs() failed: %v", err)
+	}
+	graph := buildRelationGraph(adrs)
+	if expected := []graphEdge{{From: "001", To: "SEC-001", Type: "Related to"}}; !reflect.DeepEqual(graph.Edges, expected) {
+		t.Errorf("graph edges = %+v, want %+v", graph.Edges, expected)
+	}
+}
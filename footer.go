@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// footerLinePattern matches a "Key: Value" line of a metadata footer, such
+// as "Owner: platform-team".
+var footerLinePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9 _-]*):\s+(\S.*)$`)
+
+// footerBlock returns the range of the block of "Key: Value" lines that
+// closes an ADR, after its header region. Trailing blank lines are skipped.
+func footerBlock(lines []string) (int, int, bool) {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := end
+	for start > headerEnd(lines) && footerLinePattern.MatchString(strings.TrimSpace(lines[start-1])) {
+		start--
+	}
+	return start, end, start < end
+}
+
+// parseFooter reads the metadata footer of an ADR into a map. Later lines
+// win over earlier ones with the same key.
+func parseFooter(content string) map[string]string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	start, end, ok := footerBlock(lines)
+	if !ok {
+		return nil
+	}
+	meta := make(map[string]string, end-start)
+	for _, line := range lines[start:end] {
+		match := footerLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		meta[strings.TrimSpace(match[1])] = strings.TrimSpace(match[2])
+	}
+	return meta
+}
+
+// footerValue looks key up in meta, ignoring its case.
+func footerValue(meta map[string]string, key string) (string, bool) {
+	for k, value := range meta {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// setFooterField sets key in the metadata footer of content, keeping the
+// other keys and their order. A missing key is added at the end of the
+// footer, which is started after the last line when there is none.
+func setFooterField(content, key, value string) string {
+	lines := strings.Split(content, "\n")
+	entry := fmt.Sprintf("%s: %s", key, value)
+	start, end, ok := footerBlock(lines)
+	if !ok {
+		return strings.TrimRight(content, "\n") + "\n\n" + entry + "\n"
+	}
+	for i := start; i < end; i++ {
+		match := footerLinePattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if strings.EqualFold(strings.TrimSpace(match[1]), key) {
+			lines[i] = fmt.Sprintf("%s: %s", strings.TrimSpace(match[1]), value)
+			return strings.Join(lines, "\n")
+		}
+	}
+	return strings.Join(replaceLines(lines, end-1, end-1, []string{lines[end-1], entry}), "\n")
+}
+
+// parseMetaPair splits a --meta Key=Value argument.
+func parseMetaPair(pair string) (string, string, error) {
+	key, value, ok := strings.Cut(pair, "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !ok || value == "" || !footerLinePattern.MatchString(key+": "+value) {
+		return "", "", fmt.Errorf("expected Key=Value with a key of letters, digits, spaces, '_' or '-', got %q", pair)
+	}
+	return key, value, nil
+}
+
+// filterADRsByMeta keeps the ADRs whose footer has key set to value,
+// ignoring case.
+func filterADRsByMeta(adrs []ADR, key, value string) []ADR {
+	var filtered []ADR
+	for _, adr := range adrs {
+		if got, ok := footerValue(adr.Meta, key); ok && strings.EqualFold(got, value) {
+			filtered = append(filtered, adr)
+		}
+	}
+	return filtered
+}
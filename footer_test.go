@@ -0,0 +1,112 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFooter(t *testing.T) {
+	content := "# ADR 001: Use Go\n\n**Status**: Accepted  \nDate: 2024-01-01\n\n## Context\n\nReason: speed\n\nMore text.\n\n---\n\nOwner: platform-team\nReview Cycle: yearly  \n\n"
+	expected := map[string]string{"Owner": "platform-team", "Review Cycle": "yearly"}
+	if meta := parseFooter(content); !reflect.DeepEqual(meta, expected) {
+		t.Errorf("parseFooter() = %v, want %v", meta, expected)
+	}
+
+	// Header fields are not a footer
+	if meta := parseFooter("# ADR 001: Use Go\n\nStatus: Accepted\nDate: 2024-01-01\n"); meta != nil {
+		t.Errorf("parseFooter() of a header-only ADR = %v, want nil", meta)
+	}
+}
+
+func TestSetFooterField(t *testing.T) {
+	content := "# ADR 001: Use Go\n\n## Context\n\nText.\n\nOwner: platform-team\nTier: 2\n"
+
+	updated := setFooterField(content, "owner", "data-team")
+	if expected := "# ADR 001: Use Go\n\n## Context\n\nText.\n\nOwner: data-team\nTier: 2\n"; updated != expected {
+		t.Errorf("setFooterField() = %q, want %q", updated, expected)
+	}
+	updated = setFooterField(updated, "Reviewer", "alice")
+	if expected := "# ADR 001: Use Go\n\n## Context\n\nText.\n\nOwner: data-team\nTier: 2\nReviewer: alice\n"; updated != expected {
+		t.Errorf("setFooterField() = %q, want %q", updated, expected)
+	}
+
+	if updated := setFooterField("# ADR 001: Use Go\n\n## Context\n\nText.\n", "Owner", "platform-team"); updated != "# ADR 001: Use Go\n\n## Context\n\nText.\n\nOwner: platform-team\n" {
+		t.Errorf("setFooterField() without a footer = %q", updated)
+	}
+}
+
+func TestListFilterByMeta(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	files := map[string]string{
+		"adr-001-first.md":  "# ADR 001: First\n\n**Status**: Accepted\n\n## Context\n\nText.\n\nOwner: platform-team\n",
+		"adr-002-second.md": "# ADR 002: Second\n\n**Status**: Accepted\n\n## Context\n\nText.\n\nOwner: data-team\n",
+		"adr-003-third.md":  "# ADR 003: Third\n\n**Status**: Accepted\n\n## Context\n\nText.\n",
+	}
+	for name, content := range files {
+		if err := writeFile(filepath.Join(tempDir, name), content); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	var err error
+	stdout, _ := captureOutput(t, func() { err = runListCommand([]string{"--meta", "owner=Platform-Team"}) })
+	if err != nil {
+		t.Fatalf("runListCommand() failed: %v", err)
+	}
+	if expected := "001  First  Accepted\n"; stdout != expected {
+		t.Errorf("runListCommand(--meta) = %q, want %q", stdout, expected)
+	}
+
+	if err := runListCommand([]string{"--meta", "owner"}); err == nil {
+		t.Error("runListCommand() should reject --meta without a value")
+	}
+}
+
+func TestRunCreateNoteKeepsFooter(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	path := filepath.Join(tempDir, "adr-001-use-go.md")
+	if err := writeFile(path, "# ADR 001: Use Go\n\n**Status**: Proposed  \n\n## Context\n\nText.\n"); err != nil {
+		t.Fatalf("Failed to create ADR: %v", err)
+	}
+
+	runs := [][]string{
+		{"--status", "Proposed", "--meta", "Owner=platform-team"},
+		{"--status", "Accepted", "--note", "Approved by the board"},
+		{"--status", "Accepted", "--meta", "Owner=data-team"},
+	}
+	for _, args := range runs {
+		var code int
+		_, stderr := captureOutput(t, func() {
+			code = runCreate(append([]string{"--dir", tempDir, "--number", "001", "--title", "Use Go"}, args...))
+		})
+		if code != 0 {
+			t.Fatalf("runCreate(%v) = %d, want 0 (stderr: %q)", args, code, stderr)
+		}
+	}
+
+	content, err := readFileWithRetry(path)
+	if err != nil {
+		t.Fatalf("Failed to read ADR: %v", err)
+	}
+	if meta := parseFooter(string(content)); !reflect.DeepEqual(meta, map[string]string{"Owner": "data-team"}) {
+		t.Errorf("footer = %v, want Owner: data-team only\n%s", meta, content)
+	}
+	if !strings.Contains(string(content), "## Decision Log\n\n- ") || strings.Count(string(content), "Owner:") != 1 {
+		t.Errorf("ADR = %q, want the Decision Log before a single footer", content)
+	}
+
+	adrDir = tempDir
+	stdout, _ := captureOutput(t, func() { err = runListCommand([]string{"--meta", "Owner=data-team"}) })
+	if err != nil || stdout != "001  Use Go  Accepted\n" {
+		t.Errorf("runListCommand(--meta) = %q, %v, want ADR 001", stdout, err)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// adrFS is the filesystem ADRs, templates and the index are read from and
+// written to. osFS is the real disk; tests swap in an in-memory one.
+type adrFS interface {
+	ReadDir(name string) ([]os.DirEntry, error)
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte) error
+	Remove(name string) error
+	Stat(name string) (os.FileInfo, error)
+	MkdirAll(name string) error
+}
+
+var fsys adrFS = osFS{}
+
+type osFS struct{}
+
+func (osFS) ReadDir(name string) ([]os.DirEntry, error) { return os.ReadDir(name) }
+func (osFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (osFS) Remove(name string) error                   { return os.Remove(name) }
+func (osFS) Stat(name string) (os.FileInfo, error)      { return os.Stat(name) }
+func (osFS) MkdirAll(name string) error                 { return os.MkdirAll(name, os.ModePerm) }
+
+// WriteFile writes through symlinks and replaces the file atomically: the
+// content goes to a synced temporary file in the same directory, which is
+// renamed over the target before the directory is synced. A crash leaves
+// either the old content or the new, never a truncated file.
+func (osFS) WriteFile(name string, data []byte) error {
+	path, err := resolveSymlink(name)
+	if err != nil {
+		return err
+	}
+
+	// A read-only file stays protected, as it would be when written in place
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		file.Close()
+		mode = info.Mode().Perm()
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+		return err
+	}
+
+	// Flush the content to disk before the file is referenced anywhere else
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+func syncDir(path string) error {
+	// Directories can't be opened for syncing on Windows
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
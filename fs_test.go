@@ -0,0 +1,211 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memFS is an in-memory adrFS for tests that shouldn't touch the disk.
+type memFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+func newMemFS() *memFS {
+	return &memFS{files: make(map[string][]byte), dirs: map[string]bool{".": true}}
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.dir }
+func (i memFileInfo) Sys() any           { return nil }
+func (i memFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+func notExist(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *memFS) ReadDir(name string) ([]os.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if !m.dirs[name] {
+		return nil, notExist("readdir", name)
+	}
+	var entries []os.DirEntry
+	for file, data := range m.files {
+		if filepath.Dir(file) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{filepath.Base(file), int64(len(data)), false}))
+		}
+	}
+	for dir := range m.dirs {
+		if dir != name && filepath.Dir(dir) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{filepath.Base(dir), 0, true}))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+func (m *memFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, notExist("open", name)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (m *memFS) WriteFile(name string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if !m.dirs[filepath.Dir(name)] {
+		return notExist("open", name)
+	}
+	m.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if _, ok := m.files[name]; !ok {
+		return notExist("remove", name)
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *memFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if data, ok := m.files[name]; ok {
+		return memFileInfo{filepath.Base(name), int64(len(data)), false}, nil
+	}
+	if m.dirs[name] {
+		return memFileInfo{filepath.Base(name), 0, true}, nil
+	}
+	return nil, notExist("stat", name)
+}
+
+func (m *memFS) MkdirAll(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for dir := filepath.Clean(name); !m.dirs[dir]; dir = filepath.Dir(dir) {
+		m.dirs[dir] = true
+	}
+	return nil
+}
+
+func TestInMemoryFS(t *testing.T) {
+	mem := newMemFS()
+	originalFS, originalAdrDir := fsys, adrDir
+	fsys, adrDir = mem, filepath.Join("virtual", "adr")
+	defer func() { fsys, adrDir = originalFS, originalAdrDir }()
+
+	if err := ensureDir(adrDir); err != nil {
+		t.Fatalf("ensureDir() failed: %v", err)
+	}
+	if err := writeFile(filepath.Join(adrDir, "adr-001-use-go.md"), "# ADR 001: Use Go\n\n**Status**: Accepted  \n"); err != nil {
+		t.Fatalf("writeFile() failed: %v", err)
+	}
+	if !adrExists("001") {
+		t.Error("adrExists() returned false for an in-memory ADR")
+	}
+	if result := getNextADRNumber(); result != "002" {
+		t.Errorf("getNextADRNumber() = %q, want %q", result, "002")
+	}
+
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+	index, err := mem.ReadFile(filepath.Join(adrDir, indexFile))
+	if err != nil {
+		t.Fatalf("Index was not written to the in-memory filesystem: %v", err)
+	}
+	if !strings.Contains(string(index), "- [Use Go](adr-001-use-go.md)") {
+		t.Errorf("Index content = %q, want the in-memory ADR", index)
+	}
+	if _, err := os.Stat("virtual"); !os.IsNotExist(err) {
+		t.Error("In-memory run touched the real filesystem")
+	}
+}
+
+// foldFS is a memFS that matches names regardless of case and keeps the case
+// a file was created with, like the default filesystems of macOS and Windows.
+type foldFS struct {
+	*memFS
+}
+
+func (f foldFS) resolve(name string) string {
+	dir := filepath.Dir(name)
+	entries, _ := f.memFS.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name(), filepath.Base(name)) {
+			return filepath.Join(dir, entry.Name())
+		}
+	}
+	return name
+}
+
+func (f foldFS) ReadFile(name string) ([]byte, error) { return f.memFS.ReadFile(f.resolve(name)) }
+func (f foldFS) WriteFile(name string, data []byte) error {
+	return f.memFS.WriteFile(f.resolve(name), data)
+}
+func (f foldFS) Remove(name string) error              { return f.memFS.Remove(f.resolve(name)) }
+func (f foldFS) Stat(name string) (os.FileInfo, error) { return f.memFS.Stat(f.resolve(name)) }
+
+func TestRunCreateCaseOnlyRetitle(t *testing.T) {
+	mem := newMemFS()
+	originalFS, originalAdrDir := fsys, adrDir
+	fsys = foldFS{mem}
+	defer func() { fsys, adrDir = originalFS, originalAdrDir }()
+
+	dir := filepath.Join("virtual", "adr")
+	if err := mem.MkdirAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	original := filepath.Join(dir, "adr-001-Use-go.md")
+	if err := mem.WriteFile(original, []byte("# ADR 001: Use go\n\n**Status**: Accepted  \n")); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = runCreate([]string{"--dir", dir, "--number", "001", "--status", "Accepted", "--title", "Use Go"})
+	})
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+
+	content, err := mem.ReadFile(original)
+	if err != nil {
+		t.Fatalf("Case-only retitle lost the ADR: %v", err)
+	}
+	if title := getCurrentTitle(string(content)); title != "Use Go" {
+		t.Errorf("Title = %q, want %q", title, "Use Go")
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	return strings.TrimSpace(string(output)), err
+}
+
+// gitStamp returns the configured git author and the short HEAD commit for
+// dir. Whatever can't be determined (no git, not a repository, no commits
+// yet) is left empty and reported, on one line, in the returned error.
+func gitStamp(dir string) (string, string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", "", errors.New("git is not installed")
+	}
+
+	var problems []string
+	name, err := gitOutput(dir, "config", "user.name")
+	if err != nil {
+		problems = append(problems, "git user.name is not set")
+	}
+	email, _ := gitOutput(dir, "config", "user.email")
+
+	author := name
+	if email != "" {
+		author = strings.TrimSpace(name + " <" + email + ">")
+	}
+
+	commit, err := gitOutput(dir, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		problems = append(problems, "no commit found (not a git repository or no commits yet)")
+		commit = ""
+	}
+	if len(problems) > 0 {
+		return author, commit, errors.New(strings.Join(problems, "; "))
+	}
+	return author, commit, nil
+}
+
+// withGitFooter appends a provenance footer to templates that don't place
+// {{author}} or {{commit}} themselves. Only the parts that are known go into
+// the footer, and none is added when neither is.
+func withGitFooter(template, author, commit string) string {
+	if templateUses(template, "author") || templateUses(template, "commit") {
+		return template
+	}
+	placeholder := func(key string) string {
+		if usesGoTemplate(template) {
+			return "{{." + key + "}}"
+		}
+		return "{{" + key + "}}"
+	}
+	var footer string
+	switch {
+	case author != "" && commit != "":
+		footer = "_Created by " + placeholder("author") + " at commit " + placeholder("commit") + "_"
+	case author != "":
+		footer = "_Created by " + placeholder("author") + "_"
+	case commit != "":
+		footer = "_Created at commit " + placeholder("commit") + "_"
+	default:
+		return template
+	}
+	return strings.TrimRight(template, "\n") + "\n\n" + footer + "\n"
+}
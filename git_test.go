@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitStampOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	t.Setenv("GIT_CEILING_DIRECTORIES", t.TempDir())
+	dir := t.TempDir()
+	_, commit, err := gitStamp(dir)
+	if err == nil {
+		t.Error("Expected an error outside a git repository")
+	}
+	if commit != "" {
+		t.Errorf("gitStamp() commit = %q, want empty outside a repository", commit)
+	}
+}
+
+func TestWithGitFooter(t *testing.T) {
+	template := "# ADR {{number}}: {{title}}\n"
+	tests := []struct {
+		author, commit, expected string
+	}{
+		{"Ada <ada@example.com>", "abc1234", "# ADR {{number}}: {{title}}\n\n_Created by {{author}} at commit {{commit}}_\n"},
+		{"Ada <ada@example.com>", "", "# ADR {{number}}: {{title}}\n\n_Created by {{author}}_\n"},
+		{"", "abc1234", "# ADR {{number}}: {{title}}\n\n_Created at commit {{commit}}_\n"},
+		{"", "", template},
+	}
+	for _, test := range tests {
+		if result := withGitFooter(template, test.author, test.commit); result != test.expected {
+			t.Errorf("withGitFooter(%q, %q) = %q, want %q", test.author, test.commit, result, test.expected)
+		}
+	}
+
+	custom := "# ADR {{number}}: {{title}}\n\n**Author**: {{author}}\n"
+	if result := withGitFooter(custom, "Ada", "abc1234"); result != custom {
+		t.Errorf("withGitFooter() = %q, want template with its own placeholders unchanged", result)
+	}
+}
+
+func TestNewADRContentStampGitWithoutGit(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+	t.Setenv("PATH", "")
+
+	if err := writeFile(filepath.Join(tempDir, templateFile), "# ADR {{number}}: {{title}}\n"); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	var content string
+	var err error
+	_, stderr := captureOutput(t, func() {
+		content, err = newADRContent("001", "Accepted", "Use Go", "2024-01-01", createOptions{stampGit: true})
+	})
+	if err != nil {
+		t.Fatalf("newADRContent() failed: %v", err)
+	}
+	if expected := "# ADR 001: Use Go\n"; content != expected {
+		t.Errorf("newADRContent() = %q, want %q without a footer", content, expected)
+	}
+	if strings.Count(strings.TrimSpace(stderr), "\n") != 0 || !strings.Contains(stderr, "Warning:") {
+		t.Errorf("stderr = %q, want a single-line warning", stderr)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var graphFormats = []string{"dot", "mermaid"}
+
+// graphEdge is a relation between two ADRs, always in its active direction:
+// "Superseded by" in one ADR and "Supersedes" in the other are one edge.
+type graphEdge struct {
+	From, To, Type string
+}
+
+type relationGraph struct {
+	Nodes  []string
+	Labels map[string]string
+	Edges  []graphEdge
+}
+
+// buildRelationGraph collects the relations of adrs as edges between ADR
+// numbers. Passive types such as "Superseded by" are turned around, and
+// relations recorded on both ends are only counted once. Only ADRs with a
+// relation become nodes; targets without a file are labeled by number.
+func buildRelationGraph(adrs []ADR) relationGraph {
+	graph := relationGraph{Labels: make(map[string]string)}
+	for _, adr := range adrs {
+		if adr.Number != "" {
+			graph.Labels[adr.Number] = fmt.Sprintf("ADR %s: %s", adr.Number, adr.Title)
+		}
+	}
+
+	seen := make(map[string]bool)
+	related := make(map[string]bool)
+	for _, adr := range adrs {
+		if adr.Number == "" {
+			continue
+		}
+		for _, relation := range adr.Relations {
+			edge := graphEdge{From: adr.Number, To: relation.TargetNumber, Type: relation.Type}
+			if inverse := inverseRelationType(relation.Type); strings.HasSuffix(strings.ToLower(relation.Type), " by") && inverse != relation.Type {
+				edge = graphEdge{From: relation.TargetNumber, To: adr.Number, Type: inverse}
+			}
+			key := strings.ToLower(edge.Type) + "|" + edge.From + "|" + edge.To
+			reverse := strings.ToLower(edge.Type) + "|" + edge.To + "|" + edge.From
+			inverse, known := inverseRelationTypes[strings.ToLower(edge.Type)]
+			symmetric := known && strings.EqualFold(inverse, edge.Type)
+			if edge.From == edge.To || seen[key] || (symmetric && seen[reverse]) {
+				continue
+			}
+			seen[key] = true
+			graph.Edges = append(graph.Edges, edge)
+			related[edge.From], related[edge.To] = true, true
+		}
+	}
+
+	for _, adr := range adrs {
+		if related[adr.Number] {
+			graph.Nodes = append(graph.Nodes, adr.Number)
+			delete(related, adr.Number)
+		}
+	}
+	for _, edge := range graph.Edges {
+		if related[edge.To] {
+			graph.Nodes = append(graph.Nodes, edge.To)
+			graph.Labels[edge.To] = "ADR " + edge.To
+			delete(related, edge.To)
+		}
+	}
+	return graph
+}
+
+func isSupersession(relationType string) bool {
+	return strings.EqualFold(relationType, "Supersedes")
+}
+
+func isAmendment(relationType string) bool {
+	return strings.EqualFold(relationType, "Amends")
+}
+
+var mermaidIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// mermaidID turns an ADR number into a node ID Mermaid accepts. The prefix
+// keeps IDs from starting with a digit or being a keyword such as "end".
+func mermaidID(number string) string {
+	return "adr_" + mermaidIDUnsafe.ReplaceAllString(number, "_")
+}
+
+// mermaidText escapes a label or edge text for Mermaid, which would read
+// quotes, brackets and pipes as syntax.
+var mermaidText = strings.NewReplacer(
+	`"`, "#quot;",
+	"|", "#124;",
+	"<", "#lt;",
+	">", "#gt;",
+	"[", "#91;",
+	"]", "#93;",
+)
+
+// renderMermaidGraph renders graph as a fenced graph TD block to paste into
+// Markdown. Supersessions are dashed and amendments thick.
+func renderMermaidGraph(graph relationGraph) string {
+	var b strings.Builder
+	b.WriteString("```mermaid\ngraph TD\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", mermaidID(node), mermaidText.Replace(graph.Labels[node]))
+	}
+	for _, edge := range graph.Edges {
+		arrow := "-->"
+		switch {
+		case isSupersession(edge.Type):
+			arrow = "-.->"
+		case isAmendment(edge.Type):
+			arrow = "==>"
+		}
+		fmt.Fprintf(&b, "    %s %s|%s| %s\n", mermaidID(edge.From), arrow, mermaidText.Replace(edge.Type), mermaidID(edge.To))
+	}
+	b.WriteString("```\n")
+	return b.String()
+}
+
+var dotText = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// renderDOTGraph renders graph for Graphviz. Supersessions are dashed and
+// amendments bold.
+func renderDOTGraph(graph relationGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph adrs {\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "    \"%s\" [label=\"%s\"];\n", dotText.Replace(node), dotText.Replace(graph.Labels[node]))
+	}
+	for _, edge := range graph.Edges {
+		style := ""
+		switch {
+		case isSupersession(edge.Type):
+			style = ", style=dashed"
+		case isAmendment(edge.Type):
+			style = ", style=bold"
+		}
+		fmt.Fprintf(&b, "    \"%s\" -> \"%s\" [label=\"%s\"%s];\n", dotText.Replace(edge.From), dotText.Replace(edge.To), dotText.Replace(edge.Type), style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func runGraphCommand(args []string) error {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	registerDirFlags(fs)
+	format := fs.String("format", "dot", "Output format: dot (Graphviz) or mermaid")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if !slices.Contains(graphFormats, *format) {
+		return fmt.Errorf("invalid --format %q (supported: %s)", *format, strings.Join(graphFormats, ", "))
+	}
+
+	adrs, err := loadADRs()
+	if err != nil {
+		return err
+	}
+	if *format == "mermaid" {
+		fmt.Print(renderMermaidGraph(buildRelationGraph(adrs)))
+	} else {
+		fmt.Print(renderDOTGraph(buildRelationGraph(adrs)))
+	}
+	return nil
+}
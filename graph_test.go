@@ -0,0 +1,78 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderMermaidGraph(t *testing.T) {
+	adrs := []ADR{
+		{Number: "004", Title: `Use "Postgres" [v15]`, Relations: []Relation{{Type: "Superseded by", TargetNumber: "006"}, {Type: "Amended by", TargetNumber: "005"}}},
+		{Number: "005", Title: "Read Replicas", Relations: []Relation{{Type: "Amends", TargetNumber: "004"}, {Type: "Related to", TargetNumber: "006"}}},
+		{Number: "006", Title: "Use CockroachDB", Relations: []Relation{{Type: "Supersedes", TargetNumber: "004"}, {Type: "Related to", TargetNumber: "005"}, {Type: "Depends on", TargetNumber: "009"}}},
+		{Number: "007", Title: "Unrelated"},
+	}
+
+	expected := "```mermaid\n" +
+		"graph TD\n" +
+		"    adr_004[\"ADR 004: Use #quot;Postgres#quot; #91;v15#93;\"]\n" +
+		"    adr_005[\"ADR 005: Read Replicas\"]\n" +
+		"    adr_006[\"ADR 006: Use CockroachDB\"]\n" +
+		"    adr_009[\"ADR 009\"]\n" +
+		"    adr_006 -.->|Supersedes| adr_004\n" +
+		"    adr_005 ==>|Amends| adr_004\n" +
+		"    adr_005 -->|Related to| adr_006\n" +
+		"    adr_006 -->|Depends on| adr_009\n" +
+		"```\n"
+	if result := renderMermaidGraph(buildRelationGraph(adrs)); result != expected {
+		t.Errorf("renderMermaidGraph() =\n%s\nwant\n%s", result, expected)
+	}
+}
+
+func TestMermaidID(t *testing.T) {
+	tests := map[string]string{
+		"001":     "adr_001",
+		"SEC-001": "adr_SEC_001",
+		"XIV":     "adr_XIV",
+	}
+	for number, expected := range tests {
+		if result := mermaidID(number); result != expected {
+			t.Errorf("mermaidID(%q) = %q, want %q", number, result, expected)
+		}
+	}
+}
+
+func TestRunGraphCommandDOT(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	files := map[string]string{
+		"adr-001-first.md":  "# ADR 001: First\n\n**Status**: Superseded\n\n## Relations\n\n- Superseded by: ADR 002\n",
+		"adr-002-second.md": "# ADR 002: Second\n\n**Status**: Accepted\n\n## Relations\n\n- Supersedes: ADR 001\n",
+	}
+	for name, content := range files {
+		if err := writeFile(filepath.Join(tempDir, name), content); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	var err error
+	stdout, _ := captureOutput(t, func() { err = runGraphCommand(nil) })
+	if err != nil {
+		t.Fatalf("runGraphCommand() failed: %v", err)
+	}
+	expected := "digraph adrs {\n" +
+		"    \"001\" [label=\"ADR 001: First\"];\n" +
+		"    \"002\" [label=\"ADR 002: Second\"];\n" +
+		"    \"002\" -> \"001\" [label=\"Supersedes\", style=dashed];\n" +
+		"}\n"
+	if stdout != expected {
+		t.Errorf("runGraphCommand() =\n%s\nwant\n%s", stdout, expected)
+	}
+
+	if err := runGraphCommand([]string{"--format", "svg"}); err == nil {
+		t.Error("runGraphCommand() should reject an unknown --format")
+	}
+}
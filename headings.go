@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type headingChange struct {
+	Filename    string
+	NewFilename string
+	OldHeading  string
+	NewHeading  string
+	Number      string
+	Title       string
+}
+
+func getHeadingLine(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "# ADR") {
+			return line
+		}
+	}
+	return ""
+}
+
+func setHeading(content, number, title string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "# ADR") {
+			lines[i] = fmt.Sprintf("# ADR %s: %s", number, title)
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// planHeadingSync compares every ADR's heading with its filename. By default
+// headings are rewritten from filenames; with fromHeading the files are
+// renamed after their headings instead. The filename number always wins.
+func planHeadingSync(fromHeading bool) ([]headingChange, error) {
+	files, err := readDirWithRetry(adrDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []headingChange
+	progress := newProgress("Checking headings", len(files))
+	defer progress.Finish()
+	for _, file := range files {
+		progress.Step()
+		if file.IsDir() || !isADRFile(file.Name()) {
+			continue
+		}
+		num, ok := parseADRNumber(file.Name())
+		if !ok {
+			continue
+		}
+		number := formatNumber(num)
+
+		content, err := readFileWithRetry(filepath.Join(adrDir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		heading := getHeadingLine(string(content))
+		if heading == "" {
+			continue
+		}
+		headingTitle := getCurrentTitle(string(content))
+
+		change := headingChange{Filename: file.Name(), NewFilename: file.Name(), OldHeading: heading}
+		title := headingTitle
+		if fromHeading {
+			if headingTitle != "" {
+				change.NewFilename = renderFilename(number, headingTitle, file.Name())
+			}
+		} else if toKebabCase(headingTitle) != toKebabCase(extractTitleFromFilename(file.Name())) {
+			// Only retitle when the words differ, so hand-cased headings survive
+			title = extractTitleFromFilename(file.Name())
+		}
+
+		change.Number, change.Title = number, title
+		change.NewHeading = fmt.Sprintf("# ADR %s: %s", number, title)
+		if change.NewHeading == heading && change.NewFilename == change.Filename {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func applyHeadingChange(change headingChange) error {
+	oldPath := filepath.Join(adrDir, change.Filename)
+	newPath := filepath.Join(adrDir, change.NewFilename)
+
+	if change.NewFilename != change.Filename {
+		if _, err := fsys.Stat(newPath); err == nil {
+			return fmt.Errorf("cannot rename %s: %s already exists", change.Filename, change.NewFilename)
+		}
+	}
+
+	content, err := readFileWithRetry(oldPath)
+	if err != nil {
+		return err
+	}
+
+	updated := setHeading(string(content), change.Number, change.Title)
+
+	if err := withRetry(func() error { return writeFile(newPath, updated) }); err != nil {
+		return err
+	}
+	if newPath != oldPath {
+		if err := withRetry(func() error { return fsys.Remove(oldPath) }); err != nil {
+			return err
+		}
+		debugf("removed %s", oldPath)
+		return moveCompanions(map[string]string{oldPath: newPath})
+	}
+	return nil
+}
+
+func runSyncHeadingsCommand(args []string) error {
+	fs := flag.NewFlagSet("sync-headings", flag.ContinueOnError)
+	registerDirFlags(fs)
+	from := fs.String("from", "filename", "Source of truth for titles: filename (rewrite headings) or heading (rename files)")
+	dryRun := fs.Bool("dry-run", false, "Print the changes without applying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if *from != "filename" && *from != "heading" {
+		return fmt.Errorf("invalid --from %q (supported: filename, heading)", *from)
+	}
+
+	changes, err := planHeadingSync(*from == "heading")
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("All headings match their filenames")
+		return nil
+	}
+
+	for _, change := range changes {
+		if change.NewFilename != change.Filename {
+			fmt.Printf("%s -> %s\n", change.Filename, change.NewFilename)
+		}
+		if change.NewHeading != change.OldHeading {
+			fmt.Printf("%s: %q -> %q\n", change.NewFilename, change.OldHeading, change.NewHeading)
+		}
+		if *dryRun {
+			continue
+		}
+		if err := applyHeadingChange(change); err != nil {
+			return err
+		}
+	}
+
+	if *dryRun {
+		fmt.Println("Dry run: no files were changed")
+		return nil
+	}
+	return withRetry(updateIndex)
+}
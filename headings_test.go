@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncHeadingsFromFilename(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"adr-001-use-redis.md":    "# ADR 001: Use Memcached\n\n**Status**: Accepted  \n",
+		"adr-002-grpc-vs-rest.md": "# ADR 002: gRPC vs REST\n",
+		"adr-003-event-bus.md":    "# ADR 007: Event Bus\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	changes, err := planHeadingSync(false)
+	if err != nil {
+		t.Fatalf("planHeadingSync() failed: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("planHeadingSync() = %+v, want 2 changes (hand-cased heading left alone)", changes)
+	}
+	for _, change := range changes {
+		if err := applyHeadingChange(change); err != nil {
+			t.Fatalf("applyHeadingChange() failed: %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		"adr-001-use-redis.md":    "# ADR 001: Use Redis\n\n**Status**: Accepted  \n",
+		"adr-002-grpc-vs-rest.md": "# ADR 002: gRPC vs REST\n",
+		"adr-003-event-bus.md":    "# ADR 003: Event Bus\n",
+	}
+	for file, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, file))
+		if err != nil {
+			t.Fatalf("Failed to read %q: %v", file, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s content = %q, want %q", file, string(content), want)
+		}
+	}
+}
+
+func TestSyncHeadingsFromHeading(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	err := writeFile(filepath.Join(tempDir, "adr-004-old-name.md"), "# ADR 4: Adopt Kubernetes\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	changes, err := planHeadingSync(true)
+	if err != nil {
+		t.Fatalf("planHeadingSync() failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].NewFilename != "adr-004-adopt-kubernetes.md" {
+		t.Fatalf("planHeadingSync() = %+v, want a rename to adr-004-adopt-kubernetes.md", changes)
+	}
+	if err := applyHeadingChange(changes[0]); err != nil {
+		t.Fatalf("applyHeadingChange() failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, "adr-004-old-name.md")); !os.IsNotExist(err) {
+		t.Error("Old file should have been removed after the rename")
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "adr-004-adopt-kubernetes.md"))
+	if err != nil {
+		t.Fatalf("Failed to read renamed ADR: %v", err)
+	}
+	if string(content) != "# ADR 004: Adopt Kubernetes\n" {
+		t.Errorf("Renamed ADR content = %q, want the heading number fixed", string(content))
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var preWriteHook = ""
+
+// runPreWriteHook runs the --pre-write-hook command before path is written.
+// The command gets the path as its first argument (and in ADRGEN_FILE) and
+// the content on stdin; a non-zero exit is returned as an error carrying the
+// hook's stderr.
+func runPreWriteHook(path, content string) error {
+	if strings.TrimSpace(preWriteHook) == "" {
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", preWriteHook)
+	} else {
+		cmd = exec.Command("sh", "-c", preWriteHook, "sh", path)
+	}
+	cmd.Env = append(os.Environ(), "ADRGEN_FILE="+path)
+	cmd.Stdin = strings.NewReader(content)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Stdout = os.Stderr
+
+	debugf("running pre-write hook for %s", path)
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("pre-write hook rejected %s: %s", path, message)
+		}
+		return fmt.Errorf("pre-write hook rejected %s: %v", path, err)
+	}
+	return nil
+}
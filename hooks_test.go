@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunPreWriteHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands are POSIX shell here")
+	}
+	original := preWriteHook
+	defer func() { preWriteHook = original }()
+
+	preWriteHook = `grep -q "JIRA-[0-9]" || { echo "title needs a JIRA ticket ($1)" >&2; exit 1; }`
+	if err := runPreWriteHook("adr-001-use-go.md", "# ADR 001: JIRA-12 Use Go\n"); err != nil {
+		t.Errorf("runPreWriteHook() failed for accepted content: %v", err)
+	}
+	err := runPreWriteHook("adr-001-use-go.md", "# ADR 001: Use Go\n")
+	if err == nil || !strings.Contains(err.Error(), "title needs a JIRA ticket (adr-001-use-go.md)") {
+		t.Errorf("runPreWriteHook() = %v, want the hook's stderr", err)
+	}
+}
+
+func TestRunCreatePreWriteHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands are POSIX shell here")
+	}
+	tempDir := t.TempDir()
+	originalAdrDir, originalHook := adrDir, preWriteHook
+	defer func() { adrDir, preWriteHook = originalAdrDir, originalHook }()
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--number", "001", "--status", "Accepted", "--title", "Use Go", "--pre-write-hook", "echo rejected >&2; exit 3"})
+	})
+	if code != 1 {
+		t.Errorf("runCreate() = %d with a failing hook, want 1", code)
+	}
+	if !strings.Contains(stderr, "rejected") {
+		t.Errorf("stderr = %q, want the hook's message", stderr)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "adr-001-use-go.md")); !os.IsNotExist(err) {
+		t.Error("ADR was written although the hook failed")
+	}
+}
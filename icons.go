@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var indexIcons = false
+var indexIconMap = ""
+
+var defaultStatusIcons = map[string]string{
+	"accepted":   "✅",
+	"proposed":   "🕓",
+	"rejected":   "❌",
+	"superseded": "♻️",
+	"deprecated": "⚠️",
+}
+
+// statusIcons returns the icons shown with --icons: the defaults with the
+// --icon-map overrides ("Status=icon,...") applied. Overrides may name
+// statuses adrgen doesn't know. It returns nil when icons are off.
+func statusIcons() (map[string]string, error) {
+	if !indexIcons {
+		return nil, nil
+	}
+	icons := make(map[string]string, len(defaultStatusIcons))
+	for status, icon := range defaultStatusIcons {
+		icons[status] = icon
+	}
+	if strings.TrimSpace(indexIconMap) == "" {
+		return icons, nil
+	}
+	for _, entry := range strings.Split(indexIconMap, ",") {
+		status, icon, ok := strings.Cut(entry, "=")
+		status, icon = strings.TrimSpace(status), strings.TrimSpace(icon)
+		if !ok || status == "" || icon == "" {
+			return nil, fmt.Errorf("invalid --icon-map entry %q (expected Status=icon)", entry)
+		}
+		icons[strings.ToLower(status)] = icon
+	}
+	return icons, nil
+}
+
+// statusIcon looks up the icon for status, falling back to the status text
+// in parentheses for statuses without one.
+func statusIcon(icons map[string]string, status string) string {
+	if icon, ok := icons[strings.ToLower(strings.TrimSpace(status))]; ok {
+		return icon
+	}
+	if status == "" {
+		return ""
+	}
+	return "(" + status + ")"
+}
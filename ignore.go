@@ -0,0 +1,29 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// defaultIgnorePatterns match the backup, swap and lock files editors leave
+// next to ADRs. Copies such as adr-001-title-copy.md are not ignored, since
+// a title may end in "Copy"; lint reports them as duplicate numbers.
+var defaultIgnorePatterns = []string{"*~", ".*.swp", "#*#", ".#*", "*.bak", "*.orig", "*.tmp"}
+
+// ignorePatterns is the comma-separated --ignore list of filename globs that
+// are never treated as ADRs.
+var ignorePatterns = strings.Join(defaultIgnorePatterns, ",")
+
+// ignoredBy returns the --ignore pattern matching name, if any.
+func ignoredBy(name string) (string, bool) {
+	for _, pattern := range strings.Split(ignorePatterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
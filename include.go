@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// includeDir is the subdirectory of the ADR directory that includes are
+// resolved against. Only top-level files are ADRs, so fragments kept there
+// never show up in the index or the other commands.
+const includeDir = "_includes"
+
+var includePattern = regexp.MustCompile(`\{\{\s*include\s+"([^"]+)"\s*\}\}`)
+
+// expandIncludes replaces {{include "path"}} directives with the contents of
+// the named file, resolved against the includeDir of the ADR directory. Included files may
+// include others; cycles and missing files are errors naming the path.
+func expandIncludes(template string) (string, error) {
+	return expandIncludesFrom(template, nil)
+}
+
+func expandIncludesFrom(template string, stack []string) (string, error) {
+	var expandErr error
+	result := includePattern.ReplaceAllStringFunc(template, func(directive string) string {
+		if expandErr != nil {
+			return directive
+		}
+		name := includePattern.FindStringSubmatch(directive)[1]
+		dir := filepath.Join(adrDir, includeDir)
+		if filepath.IsAbs(name) || strings.HasPrefix(filepath.Clean(name), "..") {
+			expandErr = fmt.Errorf("include %q must be a path inside %s", name, dir)
+			return directive
+		}
+
+		path := filepath.Join(dir, name)
+		for _, including := range stack {
+			if including == path {
+				expandErr = fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), path)
+				return directive
+			}
+		}
+
+		content, err := readFileWithRetry(path)
+		if err != nil {
+			expandErr = fmt.Errorf("include %s: %w", path, err)
+			return directive
+		}
+		expanded, err := expandIncludesFrom(strings.TrimSuffix(string(content), "\n"), append(stack, path))
+		if err != nil {
+			expandErr = err
+			return directive
+		}
+		return expanded
+	})
+	return result, expandErr
+}
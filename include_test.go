@@ -0,0 +1,84 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandIncludes(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"partials/footer.md":      "---\n\n{{include \"partials/attribution.md\"}}\n",
+		"partials/attribution.md": "_Follows the model of Joel Parker Henderson_\n",
+		"partials/loop-a.md":      "{{include \"partials/loop-b.md\"}}",
+		"partials/loop-b.md":      "{{include \"partials/loop-a.md\"}}",
+	}
+	for file, content := range testFiles {
+		path := filepath.Join(tempDir, includeDir, file)
+		if err := ensureDir(filepath.Dir(path)); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := writeFile(path, content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	result, err := expandIncludes("# ADR {{number}}: {{title}}\n\n{{include \"partials/footer.md\"}}\n")
+	if err != nil {
+		t.Fatalf("expandIncludes() failed: %v", err)
+	}
+	expected := "# ADR {{number}}: {{title}}\n\n---\n\n_Follows the model of Joel Parker Henderson_\n"
+	if result != expected {
+		t.Errorf("expandIncludes() = %q, want %q", result, expected)
+	}
+
+	if _, err := expandIncludes(`{{include "partials/loop-a.md"}}`); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expandIncludes() error = %v, want an include cycle", err)
+	}
+	if _, err := expandIncludes(`{{include "partials/missing.md"}}`); err == nil || !strings.Contains(err.Error(), "missing.md") {
+		t.Errorf("expandIncludes() error = %v, want the missing path", err)
+	}
+	if _, err := expandIncludes(`{{include "../outside.md"}}`); err == nil {
+		t.Error("expandIncludes() should reject paths outside the ADR directory")
+	}
+}
+
+func TestIncludesNotIndexed(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := ensureDir(filepath.Join(tempDir, includeDir)); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	testFiles := map[string]string{
+		filepath.Join(includeDir, "footer.md"): "_Reviewed by the architecture board_\n",
+		"template.md":                          "# ADR {{number}}: {{title}}\n\n**Status**: {{status}}\n\n{{include \"footer.md\"}}\n",
+		"adr-001-use-go.md":                    "# ADR 001: Use Go\n\n**Status**: Accepted\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if result, err := expandIncludes(testFiles["template.md"]); err != nil || !strings.Contains(result, "_Reviewed by the architecture board_") {
+		t.Fatalf("expandIncludes() = %q, %v, want the footer fragment", result, err)
+	}
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+	index, err := readFileWithRetry(filepath.Join(tempDir, indexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if strings.Contains(string(index), "footer") || !strings.Contains(string(index), "adr-001-use-go.md") {
+		t.Errorf("index = %q, want ADR 001 and no include fragment", index)
+	}
+}
@@ -0,0 +1,766 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// indexReadWorkers bounds how many ADR files are read concurrently.
+const indexReadWorkers = 8
+
+type ADR struct {
+	Number    string
+	Title     string
+	Status    string
+	Summary   string
+	Date      string
+	Tags      []string
+	Drivers   []string
+	Timestamp string
+	Filename  string
+	Relations []Relation
+	// Meta holds the "Key: Value" lines of the metadata footer.
+	Meta map[string]string
+}
+
+// IndexFormatter renders the list of ADRs into an index document. Extension
+// is the file extension the rendered index is written with.
+type IndexFormatter interface {
+	Format(adrs []ADR) string
+	Extension() string
+}
+
+type adrGroup struct {
+	Name string
+	ADRs []ADR
+	// Collapsed groups are folded into a <details> block where the format
+	// has one.
+	Collapsed bool
+}
+
+type markdownIndexFormatter struct{}
+
+type confluenceIndexFormatter struct{}
+
+func newIndexFormatter(format string) (IndexFormatter, error) {
+	switch format {
+	case "", "markdown":
+		return markdownIndexFormatter{}, nil
+	case "confluence":
+		return confluenceIndexFormatter{}, nil
+	case "tasklist":
+		return taskListIndexFormatter{}, nil
+	}
+	return nil, fmt.Errorf("unknown index format %q (supported: markdown, confluence, tasklist)", format)
+}
+
+func indexPath(formatter IndexFormatter) string {
+	if indexFileName != "" {
+		return filepath.Join(adrDir, indexFileName)
+	}
+	name := strings.TrimSuffix(indexFile, filepath.Ext(indexFile)) + formatter.Extension()
+	return filepath.Join(adrDir, name)
+}
+
+func firstSentence(text string) string {
+	for i := 0; i < len(text); i++ {
+		if strings.ContainsRune(".!?", rune(text[i])) && (i+1 == len(text) || text[i+1] == ' ') {
+			return text[:i+1]
+		}
+	}
+	return text
+}
+
+func extractSummary(content string) string {
+	lines := strings.Split(content, "\n")
+
+	// A dedicated "> summary:" line wins over the context paragraph
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ">"))
+		if len(trimmed) > len("summary:") && strings.EqualFold(trimmed[:len("summary:")], "summary:") {
+			return strings.TrimSpace(trimmed[len("summary:"):])
+		}
+	}
+
+	inContext := false
+	var paragraph []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "## ") {
+			if inContext {
+				break
+			}
+			inContext = strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(trimmed, "## ")), "Context")
+			continue
+		}
+		if !inContext {
+			continue
+		}
+		if trimmed == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+
+	return firstSentence(strings.Join(paragraph, " "))
+}
+
+// linkedTargets returns the names of files in the ADR directory that the
+// index or a template symlinks to, so they aren't mistaken for ADRs.
+func linkedTargets(files []os.DirEntry) map[string]bool {
+	dir, err := filepath.Abs(adrDir)
+	if err != nil {
+		return nil
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	targets := make(map[string]bool)
+	for _, file := range files {
+		if file.Type()&os.ModeSymlink == 0 || (file.Name() != indexFile && !isTemplateFile(file.Name())) {
+			continue
+		}
+		target, err := resolveSymlink(filepath.Join(dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		targetDir := filepath.Dir(target)
+		if resolved, err := filepath.EvalSymlinks(targetDir); err == nil {
+			targetDir = resolved
+		}
+		if targetDir == dir {
+			targets[filepath.Base(target)] = true
+		}
+	}
+	return targets
+}
+
+func loadADRs() ([]ADR, error) {
+	files, err := fsys.ReadDir(adrDir)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := linkedTargets(files)
+
+	debugf("scanning %s (%d entries)", adrDir, len(files))
+	var names []string
+	for _, file := range files {
+		pattern, ignored := ignoredBy(file.Name())
+		switch {
+		case file.IsDir():
+			debugf("skipped %s: directory", file.Name())
+		case ignored:
+			debugf("skipped %s: matches ignore pattern %q", file.Name(), pattern)
+		case !isADRFile(file.Name()):
+			debugf("skipped %s: not an ADR file", file.Name())
+		case linked[file.Name()]:
+			debugf("skipped %s: target of the index or template symlink", file.Name())
+		default:
+			names = append(names, file.Name())
+		}
+	}
+	debugf("found %d ADR file(s)", len(names))
+
+	sortADRFiles(names)
+
+	// Reading is I/O bound, so a few files are read at once
+	adrs := make([]ADR, len(names))
+	errs := make([]error, len(names))
+	progress := newProgress("Reading ADRs", len(names))
+	defer progress.Finish()
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(indexReadWorkers, len(names)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				adrs[i], errs[i] = loadADR(names[i])
+				progress.Step()
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return adrs, nil
+}
+
+func loadADR(name string) (ADR, error) {
+	content, err := fsys.ReadFile(filepath.Join(adrDir, name))
+	if err != nil {
+		return ADR{}, err
+	}
+
+	adr := ADR{
+		Title:     indexTitle(name, string(content)),
+		Status:    getCurrentStatus(string(content)),
+		Summary:   extractSummary(string(content)),
+		Date:      extractDate(string(content)),
+		Timestamp: extractTimestamp(string(content)),
+		Tags:      extractTags(string(content)),
+		Drivers:   parseDrivers(string(content)),
+		Meta:      parseFooter(string(content)),
+		Filename:  name,
+		Relations: parseRelations(string(content)),
+	}
+	if num, ok := parseADRNumber(name); ok {
+		adr.Number = formatNumber(num)
+	} else if cat, num, _, ok := categorizedADR(name); ok {
+		adr.Number = qualifiedNumber(cat, num)
+	} else {
+		debugf("%s has no ADR number", name)
+	}
+	return adr, nil
+}
+
+// indexTitle is the title the index shows: the filename, title-cased, or with
+// --title-case off the heading as written, falling back to the filename.
+func indexTitle(name, content string) string {
+	if indexTitleCase == "off" {
+		if title := getCurrentTitle(content); title != "" {
+			return title
+		}
+	}
+	return extractTitleFromFilename(name)
+}
+
+func extractDate(content string) string {
+	if date, ok := findField(strings.Split(content, "\n"), "Date"); ok {
+		return date.Value
+	}
+	return ""
+}
+
+// extractTags splits the comma-separated Tags field.
+func extractTags(content string) []string {
+	f, ok := findField(strings.Split(content, "\n"), "Tags")
+	if !ok {
+		return nil
+	}
+	var tags []string
+	for _, tag := range strings.Split(f.Value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// extractTimestamp returns the Timestamp field written by --datetime.
+func extractTimestamp(content string) string {
+	if timestamp, ok := findField(strings.Split(content, "\n"), "Timestamp"); ok {
+		return timestamp.Value
+	}
+	return ""
+}
+
+func parseDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", strings.TrimSpace(value))
+}
+
+// adrTime is when an ADR was decided: its Timestamp when it has a valid one,
+// otherwise the start of its Date.
+func adrTime(adr ADR) (time.Time, error) {
+	if timestamp, err := time.Parse(time.RFC3339, strings.TrimSpace(adr.Timestamp)); err == nil {
+		return timestamp, nil
+	}
+	return parseDate(adr.Date)
+}
+
+// parseDateBound parses a --since or --until value, an RFC3339 timestamp or
+// a date. A date given as an end bound covers the whole day.
+func parseDateBound(value string, end bool) (time.Time, error) {
+	if timestamp, err := time.Parse(time.RFC3339, strings.TrimSpace(value)); err == nil {
+		return timestamp, nil
+	}
+	date, err := parseDate(value)
+	if err != nil || !end {
+		return date, err
+	}
+	return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// indexDateRange parses --since and --until; unset bounds are zero.
+func indexDateRange() (time.Time, time.Time, error) {
+	var since, until time.Time
+	var err error
+	if indexSince != "" {
+		if since, err = parseDateBound(indexSince, false); err != nil {
+			return since, until, fmt.Errorf("invalid --since %q (expected YYYY-MM-DD or an RFC3339 timestamp)", indexSince)
+		}
+	}
+	if indexUntil != "" {
+		if until, err = parseDateBound(indexUntil, true); err != nil {
+			return since, until, fmt.Errorf("invalid --until %q (expected YYYY-MM-DD or an RFC3339 timestamp)", indexUntil)
+		}
+	}
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return since, until, fmt.Errorf("--until %s is before --since %s", indexUntil, indexSince)
+	}
+	return since, until, nil
+}
+
+// filterADRsByDate keeps the ADRs dated within [since, until], by their
+// Timestamp when they have one; a zero bound is open. ADRs without a
+// parseable date are dropped and counted.
+func filterADRsByDate(adrs []ADR, since, until time.Time) ([]ADR, int) {
+	var filtered []ADR
+	undated := 0
+	for _, adr := range adrs {
+		date, err := adrTime(adr)
+		if err != nil {
+			undated++
+			continue
+		}
+		if (!since.IsZero() && date.Before(since)) || (!until.IsZero() && date.After(until)) {
+			continue
+		}
+		filtered = append(filtered, adr)
+	}
+	return filtered, undated
+}
+
+// indexGroupings are the supported --group-by values.
+var indexGroupings = []string{"status", "month", "year"}
+
+func validIndexGrouping(groupBy string) bool {
+	return groupBy == "" || slices.Contains(indexGroupings, groupBy)
+}
+
+func indexGroups(adrs []ADR) []adrGroup {
+	switch indexGroupBy {
+	case "status":
+	case "month", "year":
+		return dateGroups(adrs)
+	default:
+		if indexStatusSections == "" {
+			return []adrGroup{{ADRs: adrs}}
+		}
+	}
+
+	byStatus := make(map[string][]ADR)
+	for _, adr := range adrs {
+		status, ok := normalizeStatus(adr.Status)
+		if !ok {
+			status = "Other"
+		}
+		byStatus[status] = append(byStatus[status], adr)
+	}
+
+	return statusGroups(byStatus)
+}
+
+// dateGroups groups date-sorted ADRs under their month ("May 2024") or year,
+// with undated ADRs last under "Undated".
+func dateGroups(adrs []ADR) []adrGroup {
+	layout := "January 2006"
+	if indexGroupBy == "year" {
+		layout = "2006"
+	}
+
+	var groups []adrGroup
+	var undated []ADR
+	for _, adr := range adrs {
+		date, err := parseDate(adr.Date)
+		if err != nil {
+			undated = append(undated, adr)
+			continue
+		}
+		name := date.Format(layout)
+		if len(groups) == 0 || groups[len(groups)-1].Name != name {
+			groups = append(groups, adrGroup{Name: name})
+		}
+		groups[len(groups)-1].ADRs = append(groups[len(groups)-1].ADRs, adr)
+	}
+	if len(undated) > 0 {
+		groups = append(groups, adrGroup{Name: "Undated", ADRs: undated})
+	}
+	return groups
+}
+
+// sortIndexADRs orders the index. By date (--sort date, or grouping by month
+// or year) the newest ADRs come first, or the oldest with --reverse; equal
+// dates keep number order and undated ADRs go last. Otherwise a flat index
+// is sorted by filename and groups keep number order, reversed with
+// --reverse.
+func sortIndexADRs(adrs []ADR) []ADR {
+	sorted := slices.Clone(adrs)
+	if indexSort != "date" && indexGroupBy != "month" && indexGroupBy != "year" {
+		if indexGroupBy == "" {
+			sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Filename < sorted[j].Filename })
+		}
+		if indexReverse {
+			slices.Reverse(sorted)
+		}
+		return sorted
+	}
+
+	dates := make(map[string]time.Time, len(sorted))
+	for _, adr := range sorted {
+		if date, err := adrTime(adr); err == nil {
+			dates[adr.Filename] = date
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, aDated := dates[sorted[i].Filename]
+		b, bDated := dates[sorted[j].Filename]
+		switch {
+		case aDated != bDated:
+			return aDated
+		case !a.Equal(b):
+			return a.After(b) != indexReverse
+		}
+		return sorted[i].Number < sorted[j].Number
+	})
+	return sorted
+}
+
+// loadIndexHeader reads the --index-header file, or fetches it like a
+// --template URL. A URL that can't be fetched and was never cached gives an
+// empty header, so the built-in heading is used.
+func loadIndexHeader() (string, error) {
+	if indexHeader == "" {
+		return "", nil
+	}
+	if isRemoteURL(indexHeader) {
+		return loadRemote(indexHeader, "index header", ""), nil
+	}
+	path := indexHeader
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(adrDir, path)
+	}
+	content, err := readFileWithRetry(path)
+	if err != nil {
+		return "", fmt.Errorf("reading index header: %w", err)
+	}
+	return string(content), nil
+}
+
+// writeIndexHeading starts an index with the --index-header content or, when
+// there is none, with the --index-heading title (title by default) after the
+// format's heading markup. Fragments have neither.
+func writeIndexHeading(b *strings.Builder, markup, title string) {
+	if indexFragment {
+		return
+	}
+	heading := markup + cmp.Or(indexHeading, title)
+	if header, err := loadIndexHeader(); err == nil && strings.TrimSpace(header) != "" {
+		heading = strings.TrimRight(header, "\n")
+	}
+	b.WriteString(heading + "\n\n")
+}
+
+func (markdownIndexFormatter) Extension() string {
+	return ".md"
+}
+
+func (markdownIndexFormatter) Format(adrs []ADR) string {
+	icons, _ := statusIcons()
+	var b strings.Builder
+	writeIndexHeading(&b, "# ", "📄 Architecture Decision Records")
+	if len(adrs) == 0 {
+		if indexEmptyMessage != "" {
+			b.WriteString(indexEmptyMessage + "\n")
+		}
+		return b.String()
+	}
+	for i, group := range indexGroups(adrs) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeMarkdownGroupStart(&b, group)
+		for _, adr := range group.ADRs {
+			b.WriteString(formatIndexEntry(adr, icons))
+			if indexWithSummary && adr.Summary != "" {
+				b.WriteString(" — " + adr.Summary)
+			}
+			b.WriteString("\n")
+		}
+		writeMarkdownGroupEnd(&b, group)
+	}
+	return b.String()
+}
+
+// writeMarkdownGroupStart writes the heading of a group, or opens a
+// <details> block, which GitHub renders collapsed, for collapsed groups.
+func writeMarkdownGroupStart(b *strings.Builder, group adrGroup) {
+	switch {
+	case group.Collapsed:
+		fmt.Fprintf(b, "<details>\n<summary>%s (%d)</summary>\n\n", group.Name, len(group.ADRs))
+	case group.Name != "":
+		fmt.Fprintf(b, "## %s\n\n", group.Name)
+	}
+}
+
+func writeMarkdownGroupEnd(b *strings.Builder, group adrGroup) {
+	if group.Collapsed {
+		b.WriteString("\n</details>\n")
+	}
+}
+
+// formatIndexEntry renders one markdown index line, without the newline,
+// from --entry-template or as a plain link. With icons, the default line is
+// prefixed with the status icon; templates place it with {{icon}}.
+func formatIndexEntry(adr ADR, icons map[string]string) string {
+	icon := ""
+	if icons != nil {
+		icon = statusIcon(icons, adr.Status)
+	}
+	if indexEntryTemplate == "" {
+		if icon != "" {
+			return fmt.Sprintf("- %s [%s](%s)", icon, adr.Title, adr.Filename)
+		}
+		return fmt.Sprintf("- [%s](%s)", adr.Title, adr.Filename)
+	}
+	return renderTemplateVars(indexEntryTemplate, map[string]string{
+		"number": adr.Number,
+		"title":  adr.Title,
+		"status": adr.Status,
+		"date":   adr.Date,
+		"file":   adr.Filename,
+		"icon":   icon,
+	})
+}
+
+func (confluenceIndexFormatter) Extension() string {
+	return ".wiki"
+}
+
+func (confluenceIndexFormatter) Format(adrs []ADR) string {
+	icons, _ := statusIcons()
+	escape := strings.NewReplacer("|", "\\|", "[", "\\[", "]", "\\]").Replace
+
+	header := "|| Number || Title || Status ||"
+	if indexWithSummary {
+		header = "|| Number || Title || Status || Summary ||"
+	}
+
+	var b strings.Builder
+	writeIndexHeading(&b, "h1. ", "Architecture Decision Records")
+	if len(adrs) == 0 {
+		if indexEmptyMessage != "" {
+			b.WriteString(escape(indexEmptyMessage) + "\n")
+		}
+		return b.String()
+	}
+	written := 0
+	for _, group := range indexGroups(adrs) {
+		if len(group.ADRs) == 0 {
+			continue
+		}
+		if written > 0 {
+			b.WriteString("\n")
+		}
+		written++
+
+		if group.Collapsed {
+			fmt.Fprintf(&b, "{expand:title=%s (%d)}\n", escape(group.Name), len(group.ADRs))
+		} else if group.Name != "" {
+			fmt.Fprintf(&b, "h2. %s\n\n", group.Name)
+		}
+		b.WriteString(header + "\n")
+		for _, adr := range group.ADRs {
+			status := adr.Status
+			if icon, ok := icons[strings.ToLower(status)]; ok {
+				status = icon + " " + status
+			}
+			fmt.Fprintf(&b, "| %s | [%s|%s] | %s |", adr.Number, escape(adr.Title), adr.Filename, escape(status))
+			if indexWithSummary {
+				fmt.Fprintf(&b, " %s |", escape(adr.Summary))
+			}
+			b.WriteString("\n")
+		}
+		if group.Collapsed {
+			b.WriteString("{expand}\n")
+		}
+	}
+	return b.String()
+}
+
+// renderIndex loads the ADRs, applies the date and status filters and
+// renders them with the configured formatter.
+func renderIndex() (IndexFormatter, string, error) {
+	formatter, err := newIndexFormatter(indexFormat)
+	if err != nil {
+		return nil, "", err
+	}
+	if !validIndexGrouping(indexGroupBy) {
+		return nil, "", fmt.Errorf("unknown index grouping %q", indexGroupBy)
+	}
+	if _, err := parseStatusSections(indexStatusSections); err != nil {
+		return nil, "", err
+	}
+	if indexStatusSections != "" && indexGroupBy != "" && indexGroupBy != "status" {
+		return nil, "", fmt.Errorf("--status-sections needs --group-by status")
+	}
+	if indexTitleCase != "on" && indexTitleCase != "off" {
+		return nil, "", fmt.Errorf("invalid --title-case %q (supported: on, off)", indexTitleCase)
+	}
+	if indexSort != "" && indexSort != "name" && indexSort != "date" {
+		return nil, "", fmt.Errorf("unknown index order %q (supported: name, date)", indexSort)
+	}
+	if _, err := statusIcons(); err != nil {
+		return nil, "", err
+	}
+	if _, err := loadIndexHeader(); err != nil {
+		return nil, "", err
+	}
+	if indexEntryTemplate != "" {
+		if _, ok := formatter.(markdownIndexFormatter); !ok {
+			return nil, "", fmt.Errorf("--entry-template only applies to the markdown index format")
+		}
+		if strings.Contains(indexEntryTemplate, "\n") {
+			return nil, "", fmt.Errorf("--entry-template must be a single line")
+		}
+	}
+
+	since, until, err := indexDateRange()
+	if err != nil {
+		return nil, "", err
+	}
+
+	adrs, err := loadADRs()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// A curated order overrides the sort, except for date groupings
+	adrs = sortIndexADRs(adrs)
+	if indexGroupBy != "month" && indexGroupBy != "year" {
+		entries, err := loadIndexOrder()
+		if err != nil {
+			return nil, "", err
+		}
+		if entries != nil {
+			adrs = orderADRs(adrs, entries)
+		}
+	}
+
+	if !since.IsZero() || !until.IsZero() {
+		var undated int
+		adrs, undated = filterADRsByDate(adrs, since, until)
+		if undated > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d ADR(s) without a parseable date were left out of the index\n", undated)
+		}
+	}
+	if indexStatus != "" {
+		adrs = filterADRsByStatus(adrs, strings.Split(indexStatus, ","))
+	}
+
+	return formatter, formatter.Format(adrs), nil
+}
+
+func updateIndex() error {
+	formatter, content, err := renderIndex()
+	if err != nil {
+		return err
+	}
+	_, err = writeFileIfChanged(indexPath(formatter), content)
+	return err
+}
+
+func filterADRsByStatus(adrs []ADR, wanted []string) []ADR {
+	var filtered []ADR
+	for _, adr := range adrs {
+		for _, status := range wanted {
+			if strings.EqualFold(strings.TrimSpace(status), adr.Status) {
+				filtered = append(filtered, adr)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// checkIndex compares the rendered index with the file at path, printing a
+// diff when they differ.
+func checkIndex(path, expected string) error {
+	current, err := readFileWithRetry(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	diff := unifiedDiff(path, path+" (expected)", string(current), expected)
+	if diff == "" {
+		fmt.Printf("✅ %s is up to date\n", path)
+		return nil
+	}
+	fmt.Print(diff)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s does not exist; run adrgen index to create it", path)
+	}
+	return fmt.Errorf("%s is out of date; run adrgen index to regenerate it", path)
+}
+
+func runIndexCommand(args []string) error {
+	fs := flag.NewFlagSet("index", flag.ContinueOnError)
+	registerDirFlags(fs)
+	registerIndexFlags(fs)
+	fs.StringVar(&indexStatus, "status", indexStatus, "Only list ADRs with these statuses (comma-separated, e.g., Proposed)")
+	fs.BoolVar(&indexFragment, "fragment", indexFragment, "Leave out the top-level heading so the index can be embedded in another document; printed to stdout unless --index-file is given")
+	check := fs.Bool("check", false, "Don't write the index; exit non-zero and print a diff when the file on disk is out of date")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if indexStatus != "" {
+		for _, status := range strings.Split(indexStatus, ",") {
+			if _, ok := normalizeStatus(strings.TrimSpace(status)); !ok {
+				return fmt.Errorf("invalid --status %q (supported: %s)", status, strings.Join(statuses, ", "))
+			}
+		}
+	}
+
+	if *check && indexFragment && indexFileName == "" {
+		return fmt.Errorf("--check needs --index-file when used with --fragment")
+	}
+
+	formatter, content, err := renderIndex()
+	if err != nil {
+		return err
+	}
+	if *check {
+		return checkIndex(indexPath(formatter), content)
+	}
+	if indexFragment && indexFileName == "" {
+		fmt.Print(content)
+		return nil
+	}
+	changed := true
+	err = withRetry(func() error {
+		var err error
+		changed, err = writeFileIfChanged(indexPath(formatter), content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Printf("No changes: %s is already up to date\n", indexPath(formatter))
+		return nil
+	}
+	fmt.Printf("✅ Index written to %s\n", indexPath(formatter))
+	return nil
+}
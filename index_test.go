@@ -0,0 +1,846 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdateIndexGroupByStatus(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	originalGroupBy := indexGroupBy
+	indexGroupBy = "status"
+	defer func() {
+		adrDir = originalAdrDir
+		indexGroupBy = originalGroupBy
+	}()
+
+	testFiles := map[string]string{
+		"010-later-choice.md":   "# ADR 010: Later Choice\n\n**Status**: Accepted  \n",
+		"002-first-choice.md":   "# ADR 002: First Choice\n\n**Status**: accepted  \n",
+		"003-open-question.md":  "# ADR 003: Open Question\n\n**Status**: Proposed  \n",
+		"004-unknown-status.md": "# ADR 004: Unknown Status\n\n**Status**: Parked  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, indexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index file: %v", err)
+	}
+
+	expectedContent := "# 📄 Architecture Decision Records\n\n" +
+		"## Accepted\n\n" +
+		"- [First Choice](002-first-choice.md)\n" +
+		"- [Later Choice](010-later-choice.md)\n" +
+		"\n## Proposed\n\n" +
+		"- [Open Question](003-open-question.md)\n" +
+		"\n## Other\n\n" +
+		"- [Unknown Status](004-unknown-status.md)\n"
+
+	if string(content) != expectedContent {
+		t.Errorf("Index content = %q, want %q", string(content), expectedContent)
+	}
+}
+
+func TestUpdateIndexFlatFilenameOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	for _, file := range []string{"adr-999-last-of-three.md", "adr-1000-first-of-four.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "# Title\n\n**Status**: Accepted  \n"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, indexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index file: %v", err)
+	}
+	expected := "- [First Of Four](adr-1000-first-of-four.md)\n- [Last Of Three](adr-999-last-of-three.md)\n"
+	if !strings.HasSuffix(string(content), expected) {
+		t.Errorf("Index content = %q, want the flat list in filename order", content)
+	}
+}
+
+func TestExtractSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "summary line",
+			content:  "# ADR 001: Cache\n\n> Summary: Use Redis for session caching.\n\n## Context\n\nSessions are slow. Very slow.\n",
+			expected: "Use Redis for session caching.",
+		},
+		{
+			name:     "first sentence of context",
+			content:  "# ADR 001: Cache\n\n## Context\n\nSessions are slow\nunder load. Very slow.\n\n## Decision\n",
+			expected: "Sessions are slow under load.",
+		},
+		{
+			name:     "no summary",
+			content:  "# ADR 001: Cache\n\n## Decision\n\nUse Redis.\n",
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		result := extractSummary(test.content)
+		if result != test.expected {
+			t.Errorf("%s: extractSummary() = %q, want %q", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestUpdateIndexWithSummary(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	originalWithSummary := indexWithSummary
+	indexWithSummary = true
+	defer func() {
+		adrDir = originalAdrDir
+		indexWithSummary = originalWithSummary
+	}()
+
+	testFiles := map[string]string{
+		"001-use-redis.md":  "# ADR 001: Use Redis\n\n> summary: Cache sessions in Redis.\n",
+		"002-no-summary.md": "# ADR 002: No Summary\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, indexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index file: %v", err)
+	}
+
+	expectedContent := "# 📄 Architecture Decision Records\n\n" +
+		"- [Use Redis](001-use-redis.md) — Cache sessions in Redis.\n" +
+		"- [No Summary](002-no-summary.md)\n"
+
+	if string(content) != expectedContent {
+		t.Errorf("Index content = %q, want %q", string(content), expectedContent)
+	}
+}
+
+func TestUpdateIndexConfluence(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	originalFormat := indexFormat
+	indexFormat = "confluence"
+	defer func() {
+		adrDir = originalAdrDir
+		indexFormat = originalFormat
+	}()
+
+	err := writeFile(filepath.Join(tempDir, "001-use-redis.md"), "# ADR 001: Use Redis\n\n**Status**: Accepted  \n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "README.wiki"))
+	if err != nil {
+		t.Fatalf("Failed to read index file: %v", err)
+	}
+
+	expectedContent := "h1. Architecture Decision Records\n\n" +
+		"|| Number || Title || Status ||\n" +
+		"| 001 | [Use Redis|001-use-redis.md] | Accepted |\n"
+
+	if string(content) != expectedContent {
+		t.Errorf("Index content = %q, want %q", string(content), expectedContent)
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, indexFile)); !os.IsNotExist(err) {
+		t.Error("Markdown index should not be written for the confluence format")
+	}
+}
+
+func TestNewIndexFormatterUnknown(t *testing.T) {
+	if _, err := newIndexFormatter("docx"); err == nil {
+		t.Error("Expected error for unknown index format")
+	}
+}
+
+func TestUpdateIndexThroughSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires extra privileges on Windows")
+	}
+
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = filepath.Join(tempDir, "adr")
+	defer func() { adrDir = originalAdrDir }()
+
+	publishedDir := filepath.Join(tempDir, "published")
+	for _, dir := range []string{adrDir, publishedDir} {
+		if err := ensureDir(dir); err != nil {
+			t.Fatalf("Failed to create %q: %v", dir, err)
+		}
+	}
+
+	// The index link points outside the ADR directory and doesn't exist yet
+	publishedIndex := filepath.Join(publishedDir, "adr-index.md")
+	if err := os.Symlink(publishedIndex, filepath.Join(adrDir, indexFile)); err != nil {
+		t.Fatalf("Failed to create index symlink: %v", err)
+	}
+	if err := writeFile(filepath.Join(adrDir, "001-use-redis.md"), "# ADR 001: Use Redis\n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := updateIndex(); err != nil {
+			t.Fatalf("updateIndex() failed: %v", err)
+		}
+	}
+
+	info, err := os.Lstat(filepath.Join(adrDir, indexFile))
+	if err != nil {
+		t.Fatalf("Failed to stat index: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("updateIndex() replaced the index symlink instead of writing through it")
+	}
+
+	content, err := os.ReadFile(publishedIndex)
+	if err != nil {
+		t.Fatalf("Failed to read the symlink target: %v", err)
+	}
+	expectedContent := "# 📄 Architecture Decision Records\n\n- [Use Redis](001-use-redis.md)\n"
+	if string(content) != expectedContent {
+		t.Errorf("Index content = %q, want %q", string(content), expectedContent)
+	}
+}
+
+func TestUpdateIndexSkipsSymlinkedIndexTarget(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires extra privileges on Windows")
+	}
+
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	// README.md -> index.md inside the ADR directory: index.md is not an ADR
+	if err := os.Symlink("index.md", filepath.Join(tempDir, indexFile)); err != nil {
+		t.Fatalf("Failed to create index symlink: %v", err)
+	}
+	if err := writeFile(filepath.Join(tempDir, "001-use-redis.md"), "# ADR 001: Use Redis\n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// The second run sees index.md on disk and must not list it
+	for i := 0; i < 2; i++ {
+		if err := updateIndex(); err != nil {
+			t.Fatalf("updateIndex() failed: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "index.md"))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	expectedContent := "# 📄 Architecture Decision Records\n\n- [Use Redis](001-use-redis.md)\n"
+	if string(content) != expectedContent {
+		t.Errorf("Index content = %q, want %q", string(content), expectedContent)
+	}
+}
+
+func TestFilterADRsByDate(t *testing.T) {
+	adrs := []ADR{
+		{Number: "001", Date: "2023-12-31"},
+		{Number: "002", Date: "2024-03-01"},
+		{Number: "003", Date: "soon"},
+		{Number: "004", Date: "2025-01-01"},
+	}
+
+	since, _ := parseDate("2024-01-01")
+	until, _ := parseDate("2024-12-31")
+	filtered, undated := filterADRsByDate(adrs, since, until)
+	if len(filtered) != 1 || filtered[0].Number != "002" {
+		t.Errorf("filterADRsByDate() = %+v, want only ADR 002", filtered)
+	}
+	if undated != 1 {
+		t.Errorf("filterADRsByDate() undated = %d, want 1", undated)
+	}
+
+	filtered, _ = filterADRsByDate(adrs, since, time.Time{})
+	if len(filtered) != 2 {
+		t.Errorf("filterADRsByDate() with open end = %+v, want ADRs 002 and 004", filtered)
+	}
+}
+
+func TestUpdateIndexDateFilter(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalSince, originalFile := adrDir, indexSince, indexFileName
+	adrDir, indexSince, indexFileName = tempDir, "2024-01-01", "README-2024.md"
+	defer func() { adrDir, indexSince, indexFileName = originalAdrDir, originalSince, originalFile }()
+
+	testFiles := map[string]string{
+		"adr-001-old.md": "# ADR 001: Old\n\n**Status**: Accepted  \n**Date**: 2023-05-01\n",
+		"adr-002-new.md": "# ADR 002: New\n\n**Status**: Accepted  \n**Date**: 2024-05-01\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "README-2024.md"))
+	if err != nil {
+		t.Fatalf("Failed to read filtered index: %v", err)
+	}
+	if strings.Contains(string(content), "adr-001-old.md") || !strings.Contains(string(content), "adr-002-new.md") {
+		t.Errorf("Filtered index = %q, want only ADR 002", content)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, indexFile)); !os.IsNotExist(err) {
+		t.Error("updateIndex() with --index-file should not write README.md")
+	}
+	if isADRFile("README-2024.md") {
+		t.Error("isADRFile() = true for a filtered index")
+	}
+}
+
+func BenchmarkUpdateIndex(b *testing.B) {
+	tempDir := b.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	for i := 1; i <= 5000; i++ {
+		content := fmt.Sprintf("# ADR %04d: Decision %d\n\n**Status**: Accepted  \n**Date**: 2024-01-01\n\n## Context\n\nSynthetic decision %d.\n", i, i, i)
+		if err := os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("adr-%04d-decision-%d.md", i, i)), []byte(content), 0644); err != nil {
+			b.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := updateIndex(); err != nil {
+			b.Fatalf("updateIndex() failed: %v", err)
+		}
+	}
+}
+
+func TestRenderIndexFragment(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalStatus, originalFragment := adrDir, indexStatus, indexFragment
+	adrDir, indexStatus, indexFragment = tempDir, "Proposed", true
+	defer func() { adrDir, indexStatus, indexFragment = originalAdrDir, originalStatus, originalFragment }()
+
+	testFiles := map[string]string{
+		"001-use-go.md":    "# ADR 001: Use Go\n\n**Status**: Accepted  \n",
+		"002-use-kafka.md": "# ADR 002: Use Kafka\n\n**Status**: proposed  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	_, content, err := renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	expected := "- [Use Kafka](002-use-kafka.md)\n"
+	if content != expected {
+		t.Errorf("renderIndex() = %q, want %q", content, expected)
+	}
+}
+
+func TestRenderIndexEntryTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalTemplate := adrDir, indexEntryTemplate
+	adrDir, indexEntryTemplate = tempDir, "- ADR-{{number}} — [{{title}}]({{file}}) ({{status}}, {{date}})"
+	defer func() { adrDir, indexEntryTemplate = originalAdrDir, originalTemplate }()
+
+	content := "# ADR 007: Use Redis\n\n**Status**: Accepted  \n**Date**: 2024-06-01\n"
+	if err := writeFile(filepath.Join(tempDir, "adr-007-use-redis.md"), content); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	_, index, err := renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	expected := "- ADR-007 — [Use Redis](adr-007-use-redis.md) (Accepted, 2024-06-01)\n"
+	if !strings.HasSuffix(index, "\n\n"+expected) {
+		t.Errorf("renderIndex() = %q, want entry %q", index, expected)
+	}
+
+	originalFormat := indexFormat
+	indexFormat = "confluence"
+	defer func() { indexFormat = originalFormat }()
+	if _, _, err := renderIndex(); err == nil {
+		t.Error("renderIndex() should reject --entry-template for the confluence format")
+	}
+}
+
+func TestRenderIndexIcons(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalIcons, originalIconMap := adrDir, indexIcons, indexIconMap
+	adrDir, indexIcons, indexIconMap = tempDir, true, "Proposed=📝"
+	defer func() { adrDir, indexIcons, indexIconMap = originalAdrDir, originalIcons, originalIconMap }()
+
+	testFiles := map[string]string{
+		"001-use-go.md":    "# ADR 001: Use Go\n\n**Status**: accepted  \n",
+		"002-use-kafka.md": "# ADR 002: Use Kafka\n\n**Status**: Proposed  \n",
+		"003-use-redis.md": "# ADR 003: Use Redis\n\n**Status**: Parked  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	_, content, err := renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	expected := "- ✅ [Use Go](001-use-go.md)\n- 📝 [Use Kafka](002-use-kafka.md)\n- (Parked) [Use Redis](003-use-redis.md)\n"
+	if !strings.HasSuffix(content, "\n\n"+expected) {
+		t.Errorf("renderIndex() = %q, want entries %q", content, expected)
+	}
+
+	indexIconMap = "Proposed"
+	if _, _, err := renderIndex(); err == nil {
+		t.Error("renderIndex() should reject a malformed --icon-map")
+	}
+}
+
+func TestRunIndexCheck(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := writeFile(filepath.Join(tempDir, "001-use-go.md"), "# ADR 001: Use Go\n\n**Status**: Accepted  \n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var err error
+	captureOutput(t, func() { err = runIndexCommand([]string{"--check"}) })
+	if err == nil {
+		t.Error("runIndexCommand(--check) should fail without an index")
+	}
+	if _, statErr := os.Stat(filepath.Join(tempDir, indexFile)); !os.IsNotExist(statErr) {
+		t.Error("runIndexCommand(--check) wrote the index")
+	}
+
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+	captureOutput(t, func() { err = runIndexCommand([]string{"--check"}) })
+	if err != nil {
+		t.Errorf("runIndexCommand(--check) failed for a fresh index: %v", err)
+	}
+
+	if err := writeFile(filepath.Join(tempDir, "002-use-kafka.md"), "# ADR 002: Use Kafka\n\n**Status**: Proposed  \n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	stdout, _ := captureOutput(t, func() { err = runIndexCommand([]string{"--check"}) })
+	if err == nil {
+		t.Error("runIndexCommand(--check) should fail for a stale index")
+	}
+	if !strings.Contains(stdout, "+- [Use Kafka](002-use-kafka.md)") {
+		t.Errorf("stdout = %q, want a diff adding the new ADR", stdout)
+	}
+}
+
+func TestUpdateIndexEmpty(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalMessage := adrDir, indexEmptyMessage
+	adrDir = tempDir
+	defer func() { adrDir, indexEmptyMessage = originalAdrDir, originalMessage }()
+
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, indexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index file: %v", err)
+	}
+	expected := "# 📄 Architecture Decision Records\n\nNo architecture decisions recorded yet.\n"
+	if string(content) != expected {
+		t.Errorf("Index content = %q, want %q", content, expected)
+	}
+
+	indexEmptyMessage = "Nothing decided yet, see CONTRIBUTING.md to add the first ADR."
+	_, rendered, err := renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	if !strings.HasSuffix(rendered, "\n\n"+indexEmptyMessage+"\n") {
+		t.Errorf("renderIndex() = %q, want the custom message", rendered)
+	}
+}
+
+func TestIndexHeadingAndEmptyMessageFromConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalMessage, originalHeading, originalFormat := adrDir, indexEmptyMessage, indexHeading, indexFormat
+	originalCompanions := companions
+	adrDir = tempDir
+	defer func() {
+		adrDir, indexEmptyMessage, indexHeading, indexFormat = originalAdrDir, originalMessage, originalHeading, originalFormat
+		companions = originalCompanions
+	}()
+
+	applyConfig("index-heading: Payments Decisions\nempty-index-message: \"Nothing decided yet.\"\n", nil)
+	tests := map[string]string{
+		"markdown":   "# Payments Decisions\n\nNothing decided yet.\n",
+		"confluence": "h1. Payments Decisions\n\nNothing decided yet.\n",
+	}
+	for format, expected := range tests {
+		indexFormat = format
+		_, rendered, err := renderIndex()
+		if err != nil {
+			t.Fatalf("renderIndex(%s) failed: %v", format, err)
+		}
+		if rendered != expected {
+			t.Errorf("renderIndex(%s) = %q, want %q", format, rendered, expected)
+		}
+	}
+
+	// Flags win over the config file
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	registerIndexFlags(fs)
+	if err := fs.Parse([]string{"--index-heading", "Decisions"}); err != nil {
+		t.Fatal(err)
+	}
+	applyConfig("index-heading: Payments Decisions\n", fs)
+	if indexHeading != "Decisions" {
+		t.Errorf("indexHeading = %q, want the flag's Decisions", indexHeading)
+	}
+}
+
+func TestIndexHeaderFile(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalHeader, originalFormat := adrDir, indexHeader, indexFormat
+	adrDir, indexHeader = tempDir, "_header.md"
+	defer func() { adrDir, indexHeader, indexFormat = originalAdrDir, originalHeader, originalFormat }()
+
+	if _, _, err := renderIndex(); err == nil {
+		t.Error("renderIndex() should fail when the index header file is missing")
+	}
+
+	header := "# Decisions\n\nAsk #architecture before adding one.\n"
+	if err := writeFile(filepath.Join(tempDir, "_header.md"), header); err != nil {
+		t.Fatalf("Failed to create index header: %v", err)
+	}
+	if err := writeFile(filepath.Join(tempDir, "adr-001-use-go.md"), "# ADR 001: Use Go\n"); err != nil {
+		t.Fatalf("Failed to create ADR: %v", err)
+	}
+	for _, format := range []string{"markdown", "confluence", "tasklist"} {
+		indexFormat = format
+		_, rendered, err := renderIndex()
+		if err != nil {
+			t.Fatalf("renderIndex(%s) failed: %v", format, err)
+		}
+		if !strings.HasPrefix(rendered, header+"\n") || strings.Contains(rendered, "Architecture Decision Records") {
+			t.Errorf("renderIndex(%s) = %q, want it to start with the header file", format, rendered)
+		}
+	}
+}
+
+func TestIndexHeadingIsUTF8(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, indexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index file: %v", err)
+	}
+
+	// "# 📄 " with U+1F4C4 encoded as UTF-8 and no byte order mark
+	expected := []byte{'#', ' ', 0xF0, 0x9F, 0x93, 0x84, ' '}
+	if !strings.HasPrefix(string(content), string(expected)) {
+		t.Errorf("Index starts with % x, want % x", content[:min(len(content), len(expected))], expected)
+	}
+}
+
+func TestUpdateIndexGroupByMonth(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalGroupBy, originalReverse := adrDir, indexGroupBy, indexReverse
+	adrDir, indexGroupBy = tempDir, "month"
+	defer func() { adrDir, indexGroupBy, indexReverse = originalAdrDir, originalGroupBy, originalReverse }()
+
+	testFiles := map[string]string{
+		"adr-001-first.md":   "# ADR 001: First\n\n**Date**: 2024-01-15  \n",
+		"adr-002-second.md":  "# ADR 002: Second\n\n**Date**: 2024-03-02  \n",
+		"adr-003-third.md":   "# ADR 003: Third\n\n**Date**: 2024-01-15  \n",
+		"adr-004-undated.md": "# ADR 004: Undated\n\n**Date**: soon  \n",
+		"adr-005-fifth.md":   "# ADR 005: Fifth\n\n**Date**: 2024-01-20  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	_, content, err := renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	expected := "# 📄 Architecture Decision Records\n\n" +
+		"## March 2024\n\n- [Second](adr-002-second.md)\n" +
+		"\n## January 2024\n\n- [Fifth](adr-005-fifth.md)\n- [First](adr-001-first.md)\n- [Third](adr-003-third.md)\n" +
+		"\n## Undated\n\n- [Undated](adr-004-undated.md)\n"
+	if content != expected {
+		t.Errorf("Newest first:\n%s\nwant:\n%s", content, expected)
+	}
+
+	indexGroupBy, indexReverse = "year", true
+	_, content, err = renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	expected = "# 📄 Architecture Decision Records\n\n" +
+		"## 2024\n\n- [First](adr-001-first.md)\n- [Third](adr-003-third.md)\n- [Fifth](adr-005-fifth.md)\n- [Second](adr-002-second.md)\n" +
+		"\n## Undated\n\n- [Undated](adr-004-undated.md)\n"
+	if content != expected {
+		t.Errorf("Oldest first:\n%s\nwant:\n%s", content, expected)
+	}
+}
+
+func TestRenderIndexTitleCaseOff(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalTitleCase := adrDir, indexTitleCase
+	adrDir = tempDir
+	defer func() { adrDir, indexTitleCase = originalAdrDir, originalTitleCase }()
+
+	testFiles := map[string]string{
+		"adr-001-grpc-vs-rest.md": "# ADR 001: gRPC vs REST\n",
+		"adr-002-no-heading.md":   "**Status**: Proposed  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	for titleCase, want := range map[string]string{
+		"on":  "- [Grpc Vs Rest](adr-001-grpc-vs-rest.md)\n- [No Heading](adr-002-no-heading.md)\n",
+		"off": "- [gRPC vs REST](adr-001-grpc-vs-rest.md)\n- [No Heading](adr-002-no-heading.md)\n",
+	} {
+		indexTitleCase = titleCase
+		_, content, err := renderIndex()
+		if err != nil {
+			t.Fatalf("renderIndex() failed: %v", err)
+		}
+		if !strings.HasSuffix(content, want) {
+			t.Errorf("--title-case %s index = %q, want suffix %q", titleCase, content, want)
+		}
+	}
+
+	indexTitleCase = "maybe"
+	if _, _, err := renderIndex(); err == nil {
+		t.Error("renderIndex() accepted an invalid --title-case")
+	}
+}
+
+func TestRenderIndexTaskList(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalFormat, originalDone := adrDir, indexFormat, taskListDone
+	adrDir = tempDir
+	defer func() { adrDir, indexFormat, taskListDone = originalAdrDir, originalFormat, originalDone }()
+
+	testFiles := map[string]string{
+		"adr-001-use-go.md":    "# ADR 001: Use Go\n\n**Status**: Accepted  \n",
+		"adr-002-use-rust.md":  "# ADR 002: Use Rust\n\n**Status**: Proposed  \n",
+		"adr-003-use-java.md":  "# ADR 003: Use Java\n\n**Status**: Deprecated  \n",
+		"adr-004-use-cobol.md": "# ADR 004: Use Cobol\n\n**Status**: rejected  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	indexFormat = "tasklist"
+	_, content, err := renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	expected := "# 📄 Architecture Decision Records\n\n" +
+		"- [x] [Use Go](adr-001-use-go.md) (Accepted)\n" +
+		"- [ ] [Use Rust](adr-002-use-rust.md) (Proposed)\n" +
+		"- [ ] [Use Java](adr-003-use-java.md) (Deprecated)\n" +
+		"- [x] [Use Cobol](adr-004-use-cobol.md) (rejected)\n"
+	if content != expected {
+		t.Errorf("tasklist index = %q, want %q", content, expected)
+	}
+
+	taskListDone = "Accepted, Deprecated"
+	_, content, err = renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	if !strings.Contains(content, "- [x] [Use Java]") || !strings.Contains(content, "- [ ] [Use Cobol]") {
+		t.Errorf("tasklist index with --tasklist-done %q = %q", taskListDone, content)
+	}
+}
+
+func TestRenderIndexStatusSections(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalSections, originalFormat := adrDir, indexStatusSections, indexFormat
+	adrDir = tempDir
+	defer func() { adrDir, indexStatusSections, indexFormat = originalAdrDir, originalSections, originalFormat }()
+
+	testFiles := map[string]string{
+		"adr-001-use-go.md":    "# ADR 001: Use Go\n\n**Status**: Superseded  \n",
+		"adr-002-use-rust.md":  "# ADR 002: Use Rust\n\n**Status**: Proposed  \n",
+		"adr-003-use-java.md":  "# ADR 003: Use Java\n\n**Status**: Rejected  \n",
+		"adr-004-use-zig.md":   "# ADR 004: Use Zig\n\n**Status**: Accepted  \n",
+		"adr-005-use-cobol.md": "# ADR 005: Use Cobol\n\n**Status**: Deprecated  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	indexStatusSections = "Proposed, accepted, Superseded=collapsed, Rejected=hidden"
+	_, content, err := renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	expected := "# 📄 Architecture Decision Records\n\n" +
+		"## Proposed\n\n- [Use Rust](adr-002-use-rust.md)\n\n" +
+		"## Accepted\n\n- [Use Zig](adr-004-use-zig.md)\n\n" +
+		"<details>\n<summary>Superseded (1)</summary>\n\n- [Use Go](adr-001-use-go.md)\n\n</details>\n\n" +
+		"## Deprecated\n\n- [Use Cobol](adr-005-use-cobol.md)\n"
+	if content != expected {
+		t.Errorf("index with --status-sections = %q, want %q", content, expected)
+	}
+
+	indexFormat = "confluence"
+	if _, content, err = renderIndex(); err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	if !strings.Contains(content, "{expand:title=Superseded (1)}\n") || strings.Contains(content, "Use Java") {
+		t.Errorf("confluence index with --status-sections = %q", content)
+	}
+
+	for _, invalid := range []string{"Accepted=folded", "Parked", "Accepted,accepted=hidden"} {
+		indexStatusSections = invalid
+		if _, _, err := renderIndex(); err == nil {
+			t.Errorf("renderIndex() accepted --status-sections %q", invalid)
+		}
+	}
+}
+
+func TestRenderIndexOrderFile(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalSort := adrDir, indexSort
+	adrDir = tempDir
+	defer func() { adrDir, indexSort = originalAdrDir, originalSort }()
+
+	testFiles := map[string]string{
+		"adr-001-use-go.md":   "# ADR 001: Use Go\n\n**Date**: 2024-01-01  \n",
+		"adr-002-use-rust.md": "# ADR 002: Use Rust\n\n**Date**: 2024-03-01  \n",
+		"adr-003-use-java.md": "# ADR 003: Use Java\n\n**Date**: 2024-02-01  \n",
+		"adr-004-use-zig.md":  "# ADR 004: Use Zig\n\n**Date**: 2024-04-01  \n",
+		orderFile:             "# Onboarding reading order\nadr-003-use-java.md\n\n1\n003\n99\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	indexSort = "date"
+	var content string
+	_, stderr := captureOutput(t, func() {
+		var err error
+		if _, content, err = renderIndex(); err != nil {
+			t.Fatalf("renderIndex() failed: %v", err)
+		}
+	})
+	expected := "- [Use Java](adr-003-use-java.md)\n" +
+		"- [Use Go](adr-001-use-go.md)\n" +
+		"- [Use Zig](adr-004-use-zig.md)\n" +
+		"- [Use Rust](adr-002-use-rust.md)\n"
+	if !strings.HasSuffix(content, expected) {
+		t.Errorf("index with %s = %q, want suffix %q", orderFile, content, expected)
+	}
+	if !strings.Contains(stderr, `"99"`) || !strings.Contains(stderr, "2 ADR(s) not listed") {
+		t.Errorf("stderr = %q, want warnings for the unknown entry and the unlisted ADRs", stderr)
+	}
+}
+
+func TestFilterADRsByTimestamp(t *testing.T) {
+	adrs := []ADR{
+		{Number: "001", Filename: "adr-001-a.md", Date: "2024-06-01", Timestamp: "2024-06-01T09:00:00-03:00"},
+		{Number: "002", Filename: "adr-002-b.md", Date: "2024-06-01", Timestamp: "2024-06-01T16:30:00-03:00"},
+		{Number: "003", Filename: "adr-003-c.md", Date: "2024-06-01", Timestamp: "not a time"},
+	}
+
+	since, err := parseDateBound("2024-06-01T14:00:00-03:00", false)
+	if err != nil {
+		t.Fatalf("parseDateBound() failed: %v", err)
+	}
+	filtered, _ := filterADRsByDate(adrs, since, time.Time{})
+	if len(filtered) != 1 || filtered[0].Number != "002" {
+		t.Errorf("filterADRsByDate() = %+v, want only ADR 002", filtered)
+	}
+
+	// A date --until covers the whole day; invalid timestamps fall back to the Date
+	until, err := parseDateBound("2024-06-01", true)
+	if err != nil {
+		t.Fatalf("parseDateBound() failed: %v", err)
+	}
+	if filtered, _ := filterADRsByDate(adrs, time.Time{}, until); len(filtered) != 3 {
+		t.Errorf("filterADRsByDate() until the end of the day = %+v, want all ADRs", filtered)
+	}
+
+	originalSort := indexSort
+	defer func() { indexSort = originalSort }()
+	indexSort = "date"
+	if sorted := sortIndexADRs(adrs); sorted[0].Number != "002" || sorted[1].Number != "001" {
+		t.Errorf("date order = %+v, want ADR 002 before ADR 001 by timestamp", sorted)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// journalFile records what the last mutating run changed, relative to the
+// ADR directory, so rollback can undo it.
+var journalFile = filepath.Join(".adrgen", "last-op.json")
+
+// journalEntry is a file as it was before the run first wrote or removed it.
+type journalEntry struct {
+	Path    string `json:"path"`
+	Existed bool   `json:"existed"`
+	Content []byte `json:"content,omitempty"`
+}
+
+type opJournal struct {
+	Command string         `json:"command"`
+	Time    time.Time      `json:"time"`
+	Files   []journalEntry `json:"files"`
+
+	mu   sync.Mutex
+	seen map[string]bool
+	fs   adrFS
+}
+
+// journalFS records the prior state of every file written or removed through
+// it before passing the call on.
+type journalFS struct {
+	adrFS
+	journal *opJournal
+}
+
+func (j journalFS) WriteFile(name string, data []byte) error {
+	j.journal.record(name)
+	return j.adrFS.WriteFile(name, data)
+}
+
+func (j journalFS) Remove(name string) error {
+	j.journal.record(name)
+	return j.adrFS.Remove(name)
+}
+
+func (j *opJournal) record(name string) {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.seen[path] {
+		return
+	}
+	content, err := j.fs.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		debugf("not journaling %s: %v", path, err)
+		return
+	}
+	j.seen[path] = true
+	j.Files = append(j.Files, journalEntry{Path: path, Existed: err == nil, Content: content})
+}
+
+// startJournal routes fsys through a journal for the run of args. Runs that
+// only read leave the previous journal in place.
+func startJournal(args []string) *opJournal {
+	j := &opJournal{
+		Command: strings.TrimSpace("adrgen " + strings.Join(args, " ")),
+		Time:    time.Now(),
+		seen:    make(map[string]bool),
+		fs:      fsys,
+	}
+	fsys = journalFS{adrFS: fsys, journal: j}
+	return j
+}
+
+// save writes the journal to the ADR directory when the run changed files.
+func (j *opJournal) save() error {
+	fsys = j.fs
+	if len(j.Files) == 0 {
+		return nil
+	}
+	sort.Slice(j.Files, func(a, b int) bool { return j.Files[a].Path < j.Files[b].Path })
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(adrDir, journalFile)
+	if err := fsys.MkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return fsys.WriteFile(path, append(data, '\n'))
+}
+
+func loadJournal() (*opJournal, error) {
+	path := filepath.Join(adrDir, journalFile)
+	data, err := fsys.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("nothing to roll back: %s does not exist", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var j opJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &j, nil
+}
+
+// rollback restores the files recorded in the journal: earlier content is
+// written back and files the run created are removed. The journal is deleted
+// afterwards, so only the last run can be undone.
+func rollback(j *opJournal, dryRun bool) error {
+	for _, entry := range j.Files {
+		if entry.Existed {
+			fmt.Printf("restore %s\n", entry.Path)
+		} else {
+			fmt.Printf("remove %s\n", entry.Path)
+		}
+		if dryRun {
+			continue
+		}
+
+		var err error
+		if entry.Existed {
+			content := string(entry.Content)
+			err = withRetry(func() error { return writeFile(entry.Path, content) })
+		} else if _, statErr := fsys.Stat(entry.Path); statErr == nil {
+			err = withRetry(func() error { return fsys.Remove(entry.Path) })
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if dryRun {
+		return nil
+	}
+	return fsys.Remove(filepath.Join(adrDir, journalFile))
+}
+
+func runRollbackCommand(args []string) error {
+	fs := flag.NewFlagSet("rollback", flag.ContinueOnError)
+	registerDirFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "Print the changes without applying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+
+	j, err := loadJournal()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rolling back %q from %s\n", j.Command, j.Time.Format(time.RFC3339))
+	if err := rollback(j, *dryRun); err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Println("Dry run: no files were changed")
+	}
+	return nil
+}
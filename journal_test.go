@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRollback(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalFS := adrDir, fsys
+	adrDir = tempDir
+	defer func() { adrDir, fsys = originalAdrDir, originalFS }()
+
+	oldPath := filepath.Join(tempDir, "adr-001-use-go.md")
+	newPath := filepath.Join(tempDir, "adr-001-use-rust.md")
+	if err := writeFile(oldPath, "# ADR 001: Use Go\n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	journal := startJournal([]string{"--number", "001", "--title", "Use Rust"})
+	if err := writeFile(newPath, "# ADR 001: Use Rust\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(newPath, "# ADR 001: Use Rust!\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Remove(oldPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.save(); err != nil {
+		t.Fatalf("save() failed: %v", err)
+	}
+	if _, ok := fsys.(journalFS); ok {
+		t.Fatal("save() left the journaling filesystem in place")
+	}
+
+	loaded, err := loadJournal()
+	if err != nil {
+		t.Fatalf("loadJournal() failed: %v", err)
+	}
+	if len(loaded.Files) != 2 {
+		t.Fatalf("Journal files = %+v, want the old and the new ADR once each", loaded.Files)
+	}
+	captureOutput(t, func() { err = rollback(loaded, false) })
+	if err != nil {
+		t.Fatalf("rollback() failed: %v", err)
+	}
+
+	if content, err := os.ReadFile(oldPath); err != nil || string(content) != "# ADR 001: Use Go\n" {
+		t.Errorf("Old ADR = %q (%v), want it restored", content, err)
+	}
+	if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+		t.Error("ADR created by the rolled back run was left behind")
+	}
+	if _, err := loadJournal(); err == nil {
+		t.Error("The journal should be removed after a rollback")
+	}
+}
+
+func TestJournalSkipsReadOnlyRuns(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalFS := adrDir, fsys
+	adrDir = tempDir
+	defer func() { adrDir, fsys = originalAdrDir, originalFS }()
+
+	journal := startJournal([]string{"lint"})
+	if err := journal.save(); err != nil {
+		t.Fatalf("save() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, journalFile)); !os.IsNotExist(err) {
+		t.Error("A run that changed nothing wrote a journal")
+	}
+}
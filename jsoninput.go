@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// adrPayload is the JSON accepted by --input-json. Number may be a JSON
+// number or a string.
+type adrPayload struct {
+	Number json.Number `json:"number"`
+	Status string      `json:"status"`
+	Title  string      `json:"title"`
+	Date   string      `json:"date"`
+	Author string      `json:"author"`
+	Tags   []string    `json:"tags"`
+}
+
+// readADRPayload reads and validates the payload from path, or from stdin
+// when path is "-".
+func readADRPayload(path string) (adrPayload, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = readFileWithRetry(path)
+	}
+	if err != nil {
+		return adrPayload{}, err
+	}
+	return parseADRPayload(data)
+}
+
+func parseADRPayload(data []byte) (adrPayload, error) {
+	var payload adrPayload
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		return adrPayload{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return adrPayload{}, fmt.Errorf("invalid JSON: expected a single object")
+	}
+
+	if strings.TrimSpace(payload.Title) == "" {
+		return adrPayload{}, fmt.Errorf("title is required")
+	}
+	if payload.Status == "" {
+		return adrPayload{}, fmt.Errorf("status is required")
+	}
+	if _, ok := normalizeStatus(payload.Status); !ok {
+		return adrPayload{}, fmt.Errorf("invalid status %q (supported: %s)", payload.Status, strings.Join(statuses, ", "))
+	}
+	if payload.Number != "" {
+		num, err := strconv.Atoi(payload.Number.String())
+		if err != nil || num < 0 {
+			return adrPayload{}, fmt.Errorf("invalid number %q", payload.Number)
+		}
+		payload.Number = json.Number(formatNumber(num))
+		if err := validateNumber(payload.Number.String()); err != nil {
+			return adrPayload{}, fmt.Errorf("invalid number: %w", err)
+		}
+	}
+	if payload.Date != "" {
+		if _, err := parseDate(payload.Date); err != nil {
+			return adrPayload{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", payload.Date)
+		}
+	}
+	for _, tag := range payload.Tags {
+		if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+			return adrPayload{}, fmt.Errorf("invalid tag %q", tag)
+		}
+	}
+	return payload, nil
+}
+
+// applyADRPayload fills the options the command line left unset, so flags
+// still win over the payload. Without a number the next free one is used,
+// since JSON input is never prompted for.
+func applyADRPayload(opts *createOptions, payload adrPayload) {
+	if opts.number == "" {
+		opts.number = payload.Number.String()
+		if opts.number == "" {
+			opts.number = getNextADRNumber()
+		}
+	}
+	if opts.status == "" {
+		opts.status = payload.Status
+	}
+	if opts.title == "" {
+		opts.title = strings.TrimSpace(payload.Title)
+	}
+	opts.date = payload.Date
+	opts.author = payload.Author
+	opts.tags = payload.Tags
+}
+
+// withPayloadFields adds Author and Tags fields after the Date field for
+// values the template had no placeholder for.
+func withPayloadFields(content, template string, opts createOptions) string {
+	var extra []string
+	if opts.timestamp != "" && !templateUses(template, "timestamp") {
+		extra = append(extra, formatField(fieldBold, "Timestamp", opts.timestamp)...)
+	}
+	if opts.author != "" && !templateUses(template, "author") {
+		extra = append(extra, formatField(fieldBold, "Author", opts.author)...)
+	}
+	if len(opts.tags) > 0 && !templateUses(template, "tags") {
+		extra = append(extra, formatField(fieldBold, "Tags", strings.Join(opts.tags, ", "))...)
+	}
+	return insertFields(content, extra)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseADRPayload(t *testing.T) {
+	payload, err := parseADRPayload([]byte(`{"number": 7, "status": "accepted", "title": "Use Redis", "tags": ["cache"]}`))
+	if err != nil {
+		t.Fatalf("parseADRPayload() failed: %v", err)
+	}
+	if payload.Number.String() != "007" {
+		t.Errorf("Number = %q, want %q", payload.Number, "007")
+	}
+
+	invalid := []string{
+		`{"status": "Accepted"}`,
+		`{"title": "Use Redis", "status": "Bogus"}`,
+		`{"title": "Use Redis", "status": "Accepted", "date": "June 1st"}`,
+		`{"title": "Use Redis", "status": "Accepted", "number": "12345"}`,
+		`{"title": "Use Redis", "status": "Accepted", "owner": "me"}`,
+		`{"title": "Use Redis", "status": "Accepted"} {}`,
+	}
+	for _, data := range invalid {
+		if _, err := parseADRPayload([]byte(data)); err == nil {
+			t.Errorf("parseADRPayload(%s) should have failed", data)
+		}
+	}
+}
+
+func TestRunCreateInputJSON(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	input := filepath.Join(t.TempDir(), "adr.json")
+	payload := `{"status": "Proposed", "title": "Use Redis", "date": "2024-06-01", "author": "Jane Doe", "tags": ["cache", "infra"]}`
+	if err := writeFile(input, payload); err != nil {
+		t.Fatalf("Failed to create payload file: %v", err)
+	}
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--input-json", input})
+	})
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "adr-001-use-redis.md"))
+	if err != nil {
+		t.Fatalf("ADR was not created: %v", err)
+	}
+	for _, want := range []string{"**Status**: Proposed", "**Date**: 2024-06-01  \n**Author**: Jane Doe  \n**Tags**: cache, infra\n"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("ADR content = %q, want %q", content, want)
+		}
+	}
+}
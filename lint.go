@@ -0,0 +1,497 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+type lintIssue struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (issue lintIssue) String() string {
+	if issue.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", issue.File, issue.Line, issue.Message)
+	}
+	return fmt.Sprintf("%s: %s", issue.File, issue.Message)
+}
+
+type lintFile struct {
+	Filename string
+	Number   string
+	Content  string
+}
+
+// lintContext holds what rules need to know about the whole ADR directory.
+type lintContext struct {
+	Numbers map[string]string
+}
+
+type lintRule func(file lintFile, ctx lintContext) []lintIssue
+
+var lintRules = []lintRule{
+	lintDanglingRelations,
+	lintDates,
+	lintTimestamps,
+	lintStatusPlacement,
+	lintPreviousStatus,
+}
+
+// lintFixes rewrite ADRs to resolve what the rules report, for lint --fix.
+var lintFixes = []func(content string) string{
+	fixStatusPlacement,
+	fixPreviousStatus,
+}
+
+// lintDates reports Date fields that aren't a real YYYY-MM-DD date or lie in
+// the future, since --since, --until and the exports rely on them. ADRs
+// without a Date field are left alone.
+func lintDates(file lintFile, _ lintContext) []lintIssue {
+	f, ok := findField(strings.Split(file.Content, "\n"), "Date")
+	if !ok {
+		return nil
+	}
+	issue := lintIssue{File: file.Filename, Line: f.End + 1}
+	date, err := parseDate(f.Value)
+	if err != nil {
+		issue.Message = fmt.Sprintf("Date %q is not a valid date (expected YYYY-MM-DD)", strings.TrimSpace(f.Value))
+		return []lintIssue{issue}
+	}
+	if today := time.Now().Format("2006-01-02"); date.Format("2006-01-02") > today {
+		issue.Message = fmt.Sprintf("Date %s is in the future", date.Format("2006-01-02"))
+		return []lintIssue{issue}
+	}
+	return nil
+}
+
+// lintTimestamps reports Timestamp fields that aren't RFC3339, as --since
+// and --until would silently fall back to the Date for them.
+func lintTimestamps(file lintFile, _ lintContext) []lintIssue {
+	f, ok := findField(strings.Split(file.Content, "\n"), "Timestamp")
+	if !ok {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, strings.TrimSpace(f.Value)); err != nil {
+		return []lintIssue{{File: file.Filename, Line: f.End + 1, Message: fmt.Sprintf("Timestamp %q is not an RFC3339 timestamp (e.g. 2024-06-01T14:30:00-03:00)", strings.TrimSpace(f.Value))}}
+	}
+	return nil
+}
+
+// lintStatusPlacement reports Status fields outside the header region, before
+// the first section, and any beyond the first: they make it ambiguous which
+// status the ADR has.
+func lintStatusPlacement(file lintFile, _ lintContext) []lintIssue {
+	lines := strings.Split(file.Content, "\n")
+	header, inHeader := headerField(lines, "Status")
+	var issues []lintIssue
+	for i, f := range findFields(lines, "Status") {
+		switch {
+		case inHeader && f.Line == header.Line:
+		case !inHeader && i == 0:
+			issues = append(issues, lintIssue{File: file.Filename, Line: f.Line + 1, Message: "Status field belongs in the header, before the first ## section"})
+		default:
+			issues = append(issues, lintIssue{File: file.Filename, Line: f.Line + 1, Message: fmt.Sprintf("stray Status field %q; the ADR's status is the first one", f.Value)})
+		}
+	}
+	return issues
+}
+
+// expectedPreviousStatus is the status before the current one according to
+// the Decision Log. It is only known when the log is up to date, i.e. ends
+// with the current status, and records at least two statuses.
+func expectedPreviousStatus(content string) (string, bool) {
+	changes := parseDecisionLog(content)
+	if len(changes) == 0 {
+		return "", false
+	}
+	var history []string
+	if from, ok := normalizeStatus(changes[0].From); ok {
+		history = append(history, from)
+	}
+	for _, change := range changes {
+		// Notes without a status change repeat the current status
+		if len(history) == 0 || history[len(history)-1] != change.Status {
+			history = append(history, change.Status)
+		}
+	}
+	current, _ := normalizeStatus(strings.TrimSpace(getCurrentStatus(content)))
+	if len(history) < 2 || history[len(history)-1] != current {
+		return "", false
+	}
+	return history[len(history)-2], true
+}
+
+// lintPreviousStatus reports a Previous Status field that contradicts the
+// Decision Log, which updateStatus can leave behind after several
+// transitions.
+func lintPreviousStatus(file lintFile, _ lintContext) []lintIssue {
+	f, ok := findField(strings.Split(file.Content, "\n"), "Previous Status")
+	if !ok {
+		return nil
+	}
+	expected, ok := expectedPreviousStatus(file.Content)
+	if !ok || strings.EqualFold(strings.TrimSpace(f.Value), expected) {
+		return nil
+	}
+	return []lintIssue{{File: file.Filename, Line: f.End + 1, Message: fmt.Sprintf("Previous Status %q doesn't match the Decision Log, which has %q before the current status", strings.TrimSpace(f.Value), expected)}}
+}
+
+// fixPreviousStatus sets Previous Status to what the Decision Log records.
+func fixPreviousStatus(content string) string {
+	if _, ok := findField(strings.Split(content, "\n"), "Previous Status"); !ok {
+		return content
+	}
+	expected, ok := expectedPreviousStatus(content)
+	if !ok {
+		return content
+	}
+	return setField(content, "Previous Status", expected)
+}
+
+// fixStatusPlacement keeps only the status getCurrentStatus reads and moves
+// it into the header, after the title.
+func fixStatusPlacement(content string) string {
+	lines := strings.Split(content, "\n")
+	fields := findFields(lines, "Status")
+	header, inHeader := headerField(lines, "Status")
+	if len(fields) == 0 || (inHeader && len(fields) == 1) {
+		return content
+	}
+
+	if inHeader {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if fields[i].Line != header.Line {
+				lines = replaceLines(lines, fields[i].Line, fields[i].End, nil)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	status := fields[0]
+	lines = removeFields(lines, "Status")
+	insertAt, statusLines := frontmatterEnd(lines), []string{"**Status**: " + status.Value, ""}
+	if insertAt > 0 {
+		insertAt++
+	}
+	for i, line := range lines {
+		if strings.HasPrefix(line, "# ") {
+			insertAt, statusLines = i+1, []string{"", "**Status**: " + status.Value}
+			break
+		}
+	}
+	return strings.Join(replaceLines(lines, insertAt, insertAt-1, statusLines), "\n")
+}
+
+// lintDanglingRelations reports Relations entries pointing at ADR numbers
+// that have no file.
+func lintDanglingRelations(file lintFile, ctx lintContext) []lintIssue {
+	var issues []lintIssue
+	forEachRelationLine(file.Content, func(lineNumber int, line string) {
+		for _, relation := range parseRelationLine(line) {
+			if _, ok := ctx.Numbers[relation.TargetNumber]; !ok {
+				issues = append(issues, lintIssue{
+					File:    file.Filename,
+					Line:    lineNumber,
+					Message: fmt.Sprintf("%q references ADR %s, which does not exist", relation.Type, relation.TargetNumber),
+				})
+			}
+		}
+	})
+	return issues
+}
+
+// lintJobs bounds how many ADRs are read and checked concurrently.
+var lintJobs = runtime.GOMAXPROCS(0)
+
+func lintADRs() ([]lintIssue, error) {
+	files, err := readDirWithRetry(adrDir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := lintContext{Numbers: make(map[string]string)}
+	var names []string
+	for _, file := range files {
+		if file.IsDir() || !isADRFile(file.Name()) {
+			continue
+		}
+		names = append(names, file.Name())
+		if num, ok := parseADRNumber(file.Name()); ok {
+			ctx.Numbers[formatNumber(num)] = file.Name()
+		} else if cat, num, _, ok := categorizedADR(file.Name()); ok {
+			ctx.Numbers[qualifiedNumber(cat, num)] = file.Name()
+		}
+	}
+
+	// Relations may point at archived ADRs
+	archived, err := listADRFiles(archiveDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range archived {
+		if num, ok := parseADRNumber(path.Base(name)); ok {
+			ctx.Numbers[formatNumber(num)] = name
+		} else if cat, num, _, ok := categorizedADR(path.Base(name)); ok {
+			ctx.Numbers[qualifiedNumber(cat, num)] = name
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		issues   []lintIssue
+		firstErr error
+	)
+	progress := newProgress("Linting ADRs", len(names))
+	defer progress.Finish()
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(lintJobs, 1), len(names)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				found, err := lintADR(name, ctx)
+				progress.Step()
+				mu.Lock()
+				issues = append(issues, found...)
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, name := range names {
+		jobs <- name
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	issues = append(issues, lintCaseCollisions(names)...)
+	issues = append(issues, lintDuplicateNumbers(names)...)
+	templateIssues, err := lintTemplates()
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, templateIssues...)
+
+	// Workers finish in any order, so the report is sorted by position
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Message < b.Message
+	})
+	return issues, nil
+}
+
+// lintCaseCollisions reports ADR files whose names differ only by case:
+// distinct files on Linux, but one file on macOS and Windows.
+func lintCaseCollisions(names []string) []lintIssue {
+	var issues []lintIssue
+	first := make(map[string]string)
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if other, ok := first[key]; ok {
+			issues = append(issues, lintIssue{File: name, Message: fmt.Sprintf("differs from %s only by case, which collides on case-insensitive filesystems", other)})
+			continue
+		}
+		first[key] = name
+	}
+	return issues
+}
+
+// lintDuplicateNumbers reports ADR files sharing a number, such as a copy of
+// an ADR left next to the original.
+func lintDuplicateNumbers(names []string) []lintIssue {
+	var issues []lintIssue
+	first := make(map[int]string)
+	for _, name := range names {
+		num, ok := parseADRNumber(name)
+		if !ok {
+			continue
+		}
+		// Names differing only by case are reported as case collisions
+		if other, ok := first[num]; ok {
+			if !strings.EqualFold(name, other) {
+				issues = append(issues, lintIssue{File: name, Message: fmt.Sprintf("has the same number as %s", other)})
+			}
+			continue
+		}
+		first[num] = name
+	}
+	return issues
+}
+
+// requiredPlaceholders are what a template has to fill for adrgen to read new
+// ADRs back: the number and title of the heading, and the status.
+var requiredPlaceholders = []string{"number", "title", "status"}
+
+// lintTemplates checks the templates in the ADR directory, since a broken
+// template silently produces broken ADRs. The embedded default is not
+// checked.
+func lintTemplates() ([]lintIssue, error) {
+	templates, err := listTemplates()
+	if err != nil {
+		return nil, err
+	}
+	var issues []lintIssue
+	for _, t := range templates {
+		if t.File == "(embedded)" {
+			continue
+		}
+		content, err := readFileWithRetry(filepath.Join(adrDir, t.File))
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, lintTemplate(t.File, string(content))...)
+	}
+	return issues, nil
+}
+
+// lintTemplate reports unbalanced {{ and }}, includes and Go templates that
+// don't parse, and missing requiredPlaceholders.
+func lintTemplate(name, content string) []lintIssue {
+	issues := unbalancedBraces(name, content)
+	expanded, err := expandIncludes(content)
+	if err != nil {
+		return append(issues, lintIssue{File: name, Message: err.Error()})
+	}
+	if usesGoTemplate(expanded) {
+		if _, err := template.New(name).Parse(expanded); err != nil {
+			return append(issues, lintIssue{File: name, Message: err.Error()})
+		}
+	}
+	for _, key := range requiredPlaceholders {
+		if !templateUses(expanded, key) {
+			issues = append(issues, lintIssue{File: name, Message: fmt.Sprintf("no {{%s}} placeholder, so new ADRs won't record their %s", key, key)})
+		}
+	}
+	return issues
+}
+
+// unbalancedBraces reports a {{ that isn't closed before the next one or the
+// end of the template, and a }} that closes nothing.
+func unbalancedBraces(name, content string) []lintIssue {
+	var issues []lintIssue
+	open := 0
+	for i, line := range strings.Split(content, "\n") {
+		for rest := line; ; {
+			opening, closing := strings.Index(rest, "{{"), strings.Index(rest, "}}")
+			if opening < 0 && closing < 0 {
+				break
+			}
+			if opening >= 0 && (closing < 0 || opening < closing) {
+				if open > 0 {
+					issues = append(issues, lintIssue{File: name, Line: open, Message: "{{ is never closed with }}"})
+				}
+				open, rest = i+1, rest[opening+2:]
+				continue
+			}
+			if open == 0 {
+				issues = append(issues, lintIssue{File: name, Line: i + 1, Message: "}} without an opening {{"})
+			}
+			open, rest = 0, rest[closing+2:]
+		}
+	}
+	if open > 0 {
+		issues = append(issues, lintIssue{File: name, Line: open, Message: "{{ is never closed with }}"})
+	}
+	return issues
+}
+
+// lintADR reads one ADR and runs every rule on it.
+func lintADR(name string, ctx lintContext) ([]lintIssue, error) {
+	content, err := readFileWithRetry(filepath.Join(adrDir, name))
+	if err != nil {
+		return nil, err
+	}
+	file := lintFile{Filename: name, Content: string(content)}
+	if num, ok := parseADRNumber(name); ok {
+		file.Number = formatNumber(num)
+	}
+
+	var issues []lintIssue
+	for _, rule := range lintRules {
+		issues = append(issues, rule(file, ctx)...)
+	}
+	return issues, nil
+}
+
+// fixADRs applies the lintFixes to every ADR, writing those that change.
+func fixADRs() error {
+	names, err := listADRFiles("")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		path := filepath.Join(adrDir, name)
+		content, err := readFileWithRetry(path)
+		if err != nil {
+			return err
+		}
+		fixed := string(content)
+		for _, fix := range lintFixes {
+			fixed = fix(fixed)
+		}
+		if fixed == string(content) {
+			continue
+		}
+		if err := withRetry(func() error { return writeFile(path, fixed) }); err != nil {
+			return err
+		}
+		fmt.Printf("Fixed %s\n", path)
+	}
+	return nil
+}
+
+func runLintCommand(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	registerDirFlags(fs)
+	fix := fs.Bool("fix", false, "Rewrite ADRs to fix the problems that can be fixed automatically before checking")
+	fs.IntVar(&lintJobs, "jobs", lintJobs, "Number of ADRs checked in parallel (defaults to the number of CPUs)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if lintJobs < 1 {
+		return fmt.Errorf("invalid --jobs %d: must be at least 1", lintJobs)
+	}
+
+	if *fix {
+		if err := fixADRs(); err != nil {
+			return err
+		}
+	}
+
+	issues, err := lintADRs()
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%d problem(s) found", len(issues))
+	}
+	fmt.Println("✅ No problems found")
+	return nil
+}
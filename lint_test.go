@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLintDanglingRelations(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"adr-001-use-postgres.md": "# ADR 001: Use Postgres\n\n## Relations\n\n- Replaced by ADR: 'adr-002-use-cockroach.md'\n",
+		"adr-002-use-cockroach.md": `# ADR 002: Use Cockroach
+
+## Relations
+
+- Replaces ADR: 'adr-001-use-postgres.md'
+- Replaced by ADR: 'adr-XXXX.md' _(if applicable)_
+- Depends on: ADR 099
+- Related to: [ADR 003](adr-003-missing.md), adr-001
+`,
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	issues, err := lintADRs()
+	if err != nil {
+		t.Fatalf("lintADRs() failed: %v", err)
+	}
+
+	expected := []lintIssue{
+		{File: "adr-002-use-cockroach.md", Line: 7, Message: `"Depends on" references ADR 099, which does not exist`},
+		{File: "adr-002-use-cockroach.md", Line: 8, Message: `"Related to" references ADR 003, which does not exist`},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("lintADRs() = %+v, want %+v", issues, expected)
+	}
+}
+
+func TestLintCategorizedRelations(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := os.Mkdir(filepath.Join(tempDir, archiveDir), 0755); err != nil {
+		t.Fatalf("Failed to create archive directory: %v", err)
+	}
+	testFiles := map[string]string{
+		"adr-001-use-vault.md":         "# ADR 001: Use Vault\n\n## Relations\n\n- Related to: ADR SEC-001\n- Depends on: ADR SEC-002\n",
+		"adr-002-on-call.md":           "# ADR 002: On Call\n\n## Relations\n\n- Related to: ADR OPS-001\n",
+		"adr-SEC-001-rotate-keys.md":   "# ADR SEC-001: Rotate Keys\n",
+		"archive/adr-OPS-001-pager.md": "# ADR OPS-001: Pager\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	issues, err := lintADRs()
+	if err != nil {
+		t.Fatalf("lintADRs() failed: %v", err)
+	}
+
+	expected := []lintIssue{
+		{File: "adr-001-use-vault.md", Line: 6, Message: `"Depends on" references ADR SEC-002, which does not exist`},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("lintADRs() = %+v, want %+v", issues, expected)
+	}
+}
+
+func TestLintDates(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []lintIssue
+	}{
+		{"valid", "# ADR 001: A\n\n**Date**: 2024-02-29  \n", nil},
+		{"no date", "# ADR 001: A\n\n**Status**: Accepted  \n", nil},
+		{"not a date", "# ADR 001: A\n\n**Date**: soon  \n", []lintIssue{{File: "adr-001-a.md", Line: 3, Message: `Date "soon" is not a valid date (expected YYYY-MM-DD)`}}},
+		{"impossible date", "# ADR 001: A\n\nDate: 2023-02-30  \n", []lintIssue{{File: "adr-001-a.md", Line: 3, Message: `Date "2023-02-30" is not a valid date (expected YYYY-MM-DD)`}}},
+		{"future", "# ADR 001: A\n\n**Date**: 2999-01-01  \n", []lintIssue{{File: "adr-001-a.md", Line: 3, Message: "Date 2999-01-01 is in the future"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := lintDates(lintFile{Filename: "adr-001-a.md", Number: "001", Content: tt.content}, lintContext{})
+			if !reflect.DeepEqual(issues, tt.expected) {
+				t.Errorf("lintDates() = %v, want %v", issues, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLintADRsOrderIndependentOfJobs(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalJobs := adrDir, lintJobs
+	adrDir = tempDir
+	defer func() { adrDir, lintJobs = originalAdrDir, originalJobs }()
+
+	for i := 1; i <= 50; i++ {
+		content := fmt.Sprintf("# ADR %03d: D\n\n**Date**: soon  \n\n## Relations\n\n- Depends on: ADR 9%02d\n", i, i)
+		if err := writeFile(filepath.Join(tempDir, fmt.Sprintf("adr-%03d-d.md", i)), content); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	lintJobs = 1
+	serial, err := lintADRs()
+	if err != nil {
+		t.Fatalf("lintADRs() failed: %v", err)
+	}
+	if len(serial) != 100 {
+		t.Fatalf("lintADRs() found %d issues, want 100", len(serial))
+	}
+	for _, jobs := range []int{2, 8, 64} {
+		lintJobs = jobs
+		parallel, err := lintADRs()
+		if err != nil {
+			t.Fatalf("lintADRs() with --jobs %d failed: %v", jobs, err)
+		}
+		if !reflect.DeepEqual(parallel, serial) {
+			t.Errorf("lintADRs() with --jobs %d reported issues in a different order than with --jobs 1", jobs)
+		}
+	}
+}
+
+func BenchmarkLintADRs(b *testing.B) {
+	tempDir := b.TempDir()
+	originalAdrDir, originalJobs := adrDir, lintJobs
+	adrDir = tempDir
+	defer func() { adrDir, lintJobs = originalAdrDir, originalJobs }()
+
+	for i := 1; i <= 2000; i++ {
+		content := fmt.Sprintf("# ADR %04d: Decision %d\n\n**Status**: Accepted  \n**Date**: 2024-01-01\n\n## Context\n\nSynthetic decision %d.\n\n## Relations\n\n- Depends on: ADR %04d\n", i, i, i, i-1)
+		if err := os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("adr-%04d-decision-%d.md", i, i)), []byte(content), 0644); err != nil {
+			b.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	for _, jobs := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			lintJobs = jobs
+			for i := 0; i < b.N; i++ {
+				if _, err := lintADRs(); err != nil {
+					b.Fatalf("lintADRs() failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestLintCaseCollisions(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	for _, file := range []string{"adr-001-Cache.md", "adr-001-cache.md", "adr-002-queue.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "# ADR 001: Cache\n"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	issues, err := lintADRs()
+	if err != nil {
+		t.Fatalf("lintADRs() failed: %v", err)
+	}
+	expected := []lintIssue{{File: "adr-001-cache.md", Message: "differs from adr-001-Cache.md only by case, which collides on case-insensitive filesystems"}}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("lintADRs() = %+v, want %+v", issues, expected)
+	}
+}
+
+func TestLintDuplicateNumbers(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	for _, file := range []string{"adr-001-cache.md", "adr-001-cache-copy.md", "adr-002-database-copy.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "# ADR 001: Cache\n"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	issues, err := lintADRs()
+	if err != nil {
+		t.Fatalf("lintADRs() failed: %v", err)
+	}
+	expected := []lintIssue{{File: "adr-001-cache.md", Message: "has the same number as adr-001-cache-copy.md"}}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("lintADRs() = %+v, want %+v", issues, expected)
+	}
+}
+
+func TestLintStatusPlacement(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []lintIssue
+		fixed    string
+	}{
+		{
+			name:    "header status",
+			content: "# ADR 001: A\n\n**Status**: Accepted  \n\n## Context\n\nText.\n",
+			fixed:   "# ADR 001: A\n\n**Status**: Accepted  \n\n## Context\n\nText.\n",
+		},
+		{
+			name:    "section-style status",
+			content: "# ADR 001: A\n\n## Status\n\nAccepted\n\n## Context\n\nText.\n",
+			fixed:   "# ADR 001: A\n\n## Status\n\nAccepted\n\n## Context\n\nText.\n",
+		},
+		{
+			name:     "stray status in a section",
+			content:  "# ADR 001: A\n\n**Status**: Accepted  \n\n## Consequences\n\n**Status**: Proposed  \n",
+			expected: []lintIssue{{File: "adr-001-a.md", Line: 7, Message: `stray Status field "Proposed"; the ADR's status is the first one`}},
+			fixed:    "# ADR 001: A\n\n**Status**: Accepted  \n\n## Consequences\n\n",
+		},
+		{
+			name:     "status only inside a section",
+			content:  "# ADR 001: A\n\n## Context\n\nStatus: Proposed\n",
+			expected: []lintIssue{{File: "adr-001-a.md", Line: 5, Message: "Status field belongs in the header, before the first ## section"}},
+			fixed:    "# ADR 001: A\n\n**Status**: Proposed\n\n## Context\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := lintFile{Filename: "adr-001-a.md", Number: "001", Content: tt.content}
+			if issues := lintStatusPlacement(file, lintContext{}); !reflect.DeepEqual(issues, tt.expected) {
+				t.Errorf("lintStatusPlacement() = %+v, want %+v", issues, tt.expected)
+			}
+			fixed := fixStatusPlacement(tt.content)
+			if fixed != tt.fixed {
+				t.Errorf("fixStatusPlacement() = %q, want %q", fixed, tt.fixed)
+			}
+			if issues := lintStatusPlacement(lintFile{Filename: file.Filename, Content: fixed}, lintContext{}); len(issues) > 0 {
+				t.Errorf("fixed ADR still has issues: %+v", issues)
+			}
+		})
+	}
+
+	stray := "# ADR 001: A\n\n**Status**: Accepted  \n\n## Notes\n\n**Status**: Rejected  \n"
+	if status := getCurrentStatus(stray); status != "Accepted" {
+		t.Errorf("getCurrentStatus() = %q, want the header status", status)
+	}
+	if status := getCurrentStatus("## Notes\n\n**Status**: Rejected\n\n**Status**: Accepted\n"); status != "Rejected" {
+		t.Errorf("getCurrentStatus() without a header status = %q, want the first one", status)
+	}
+}
+
+func TestLintPreviousStatus(t *testing.T) {
+	log := "\n## Decision Log\n\n- 2024-01-01: Proposed → Accepted — approved\n- 2024-02-01: Accepted — still valid\n- 2024-03-01: Accepted → Deprecated — replaced\n"
+	tests := []struct {
+		name     string
+		content  string
+		expected []lintIssue
+		fixed    string
+	}{
+		{
+			name:    "consistent",
+			content: "# ADR 001: A\n\n**Status**: Deprecated  \n**Previous Status**: Accepted  \n" + log,
+			fixed:   "# ADR 001: A\n\n**Status**: Deprecated  \n**Previous Status**: Accepted  \n" + log,
+		},
+		{
+			name:     "stale previous status",
+			content:  "# ADR 001: A\n\n**Status**: Deprecated  \n**Previous Status**: Proposed  \n" + log,
+			expected: []lintIssue{{File: "adr-001-a.md", Line: 4, Message: `Previous Status "Proposed" doesn't match the Decision Log, which has "Accepted" before the current status`}},
+			fixed:    "# ADR 001: A\n\n**Status**: Deprecated  \n**Previous Status**: Accepted  \n" + log,
+		},
+		{
+			name:    "log behind the current status",
+			content: "# ADR 001: A\n\n**Status**: Superseded  \n**Previous Status**: Deprecated  \n" + log,
+			fixed:   "# ADR 001: A\n\n**Status**: Superseded  \n**Previous Status**: Deprecated  \n" + log,
+		},
+		{
+			name:    "no previous status field",
+			content: "# ADR 001: A\n\n**Status**: Deprecated  \n" + log,
+			fixed:   "# ADR 001: A\n\n**Status**: Deprecated  \n" + log,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := lintFile{Filename: "adr-001-a.md", Number: "001", Content: tt.content}
+			if issues := lintPreviousStatus(file, lintContext{}); !reflect.DeepEqual(issues, tt.expected) {
+				t.Errorf("lintPreviousStatus() = %+v, want %+v", issues, tt.expected)
+			}
+			if fixed := fixPreviousStatus(tt.content); fixed != tt.fixed {
+				t.Errorf("fixPreviousStatus() = %q, want %q", fixed, tt.fixed)
+			}
+		})
+	}
+}
+
+func TestLintTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []lintIssue
+	}{
+		{
+			name:    "valid",
+			content: "# ADR {{number}}: {{title}}\n\n**Status**: {{status}}\n",
+		},
+		{
+			name:    "valid go template",
+			content: "# ADR {{.Number}}: {{.Title}}\n\n**Status**: {{.Status}}\n{{range .Tags}}- {{.}}\n{{end}}",
+		},
+		{
+			name:    "unbalanced braces",
+			content: "# ADR {{number}: {{title}}\n\n**Status**: {{status}}\nOwner: owner}}\n",
+			expected: []lintIssue{
+				{File: "template.md", Line: 1, Message: "{{ is never closed with }}"},
+				{File: "template.md", Line: 4, Message: "}} without an opening {{"},
+				{File: "template.md", Message: "no {{number}} placeholder, so new ADRs won't record their number"},
+			},
+		},
+		{
+			name:    "missing status",
+			content: "# ADR {{number}}: {{title}}\n",
+			expected: []lintIssue{
+				{File: "template.md", Message: "no {{status}} placeholder, so new ADRs won't record their status"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if issues := lintTemplate("template.md", tt.content); !reflect.DeepEqual(issues, tt.expected) {
+				t.Errorf("lintTemplate() = %+v, want %+v", issues, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLintADRsReportsBrokenTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := writeFile(filepath.Join(tempDir, "adr-001-a.md"), "# ADR 001: A\n\n**Status**: Accepted\n"); err != nil {
+		t.Fatalf("Failed to create ADR: %v", err)
+	}
+	if err := writeFile(filepath.Join(tempDir, "template-light.md"), "# {{title}}\n\n**Status**: {{status}\n"); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	issues, err := lintADRs()
+	if err != nil {
+		t.Fatalf("lintADRs() failed: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("lintADRs() = %+v, want the unclosed {{ and the missing {{number}} and {{status}} in template-light.md", issues)
+	}
+	for _, issue := range issues {
+		if issue.File != "template-light.md" {
+			t.Errorf("lintADRs() reported %v, want only template-light.md", issue)
+		}
+	}
+}
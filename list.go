@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// filterADRsByDriver keeps the ADRs with a decision driver containing text,
+// ignoring case.
+func filterADRsByDriver(adrs []ADR, text string) []ADR {
+	text = strings.ToLower(text)
+	var filtered []ADR
+	for _, adr := range adrs {
+		for _, driver := range adr.Drivers {
+			if strings.Contains(strings.ToLower(driver), text) {
+				filtered = append(filtered, adr)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func runListCommand(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	registerDirFlags(fs)
+	driverContains := fs.String("driver-contains", "", "Only list ADRs with a decision driver containing this text (case-insensitive)")
+	var meta stringList
+	fs.Var(&meta, "meta", "Only list ADRs whose metadata footer has Key set to Value, as Key=Value (repeatable, case-insensitive)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+
+	adrs, err := loadADRs()
+	if err != nil {
+		return err
+	}
+	if *driverContains != "" {
+		adrs = filterADRsByDriver(adrs, *driverContains)
+	}
+	for _, pair := range meta {
+		key, value, err := parseMetaPair(pair)
+		if err != nil {
+			return fmt.Errorf("invalid --meta: %v", err)
+		}
+		adrs = filterADRsByMeta(adrs, key, value)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, adr := range adrs {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", adr.Number, adr.Title, adr.Status)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+var verbose = false
+
+var logger = log.New(os.Stderr, "adrgen: ", log.LstdFlags)
+
+// debugf logs a diagnostic step to stderr when --verbose is set.
+func debugf(format string, args ...any) {
+	if verbose {
+		logger.Printf(format, args...)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerboseLogging(t *testing.T) {
+	tempDir := t.TempDir()
+	var out bytes.Buffer
+	originalAdrDir, originalVerbose, originalLogger := adrDir, verbose, logger
+	adrDir, logger = tempDir, log.New(&out, "", 0)
+	defer func() { adrDir, verbose, logger = originalAdrDir, originalVerbose, originalLogger }()
+
+	for _, file := range []string{"adr-001-first.md", "notes.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	verbose = false
+	getNextADRNumber()
+	if out.Len() != 0 {
+		t.Errorf("Logged without --verbose: %q", out.String())
+	}
+
+	verbose = true
+	getNextADRNumber()
+	for _, expected := range []string{"skipped notes.md", "allocated number 002"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Verbose log = %q, want it to mention %q", out.String(), expected)
+		}
+	}
+}
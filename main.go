@@ -1,207 +1,544 @@
 package main
 
 import (
+	"cmp"
+	"errors"
+	"flag"
 	"fmt"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/manifoldco/promptui"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
-var adrDir = "docs/adr"
+var adrDir = defaultADRDir
+var templateName = ""
+var separator = "-"
+var filenameTemplate = ""
+var adrType = "adr"
+var category = ""
+var numberWidth = 3
+var fillGaps = false
+var baseNumber = 1
+var indexGroupBy = ""
+var indexSort = ""
+var indexReverse = false
+var indexTitleCase = "on"
+var indexWithSummary = false
+var indexFormat = "markdown"
+var indexFileName = ""
+var indexSince = ""
+var indexUntil = ""
+var indexStatus = ""
+var indexFragment = false
+var indexEntryTemplate = ""
+var indexEmptyMessage = "No architecture decisions recorded yet."
+var indexHeader = ""
+var indexHeading = ""
+var noPreviousStatus = false
+var smartSlug = false
+var maxTitleLength = 0
+var defaultStatus = ""
+var retryAttempts = 1
+var retryDelay = 200 * time.Millisecond
+
+var statuses = []string{"Accepted", "Proposed", "Rejected", "Superseded", "Deprecated"}
 
 const indexFile = "README.md"
 const templateFile = "template.md"
 
+// defaultTemplate is used when the ADR directory has no template.md.
+const defaultTemplate = `# ADR {{number}}: {{title}}
+
+**Status**: {{status}}  
+**Date**: {{date}}
+
+---
+
+## Context
+
+Describe here the problem, need, or motivation for this decision. Include the current scenario, technical or business constraints, and the factors influencing the choice.
+
+## Decision
+
+Clearly state the decision made. For example:
+
+> We decided to adopt the XYZ framework for developing REST APIs in the ABC project.
+
+## Considered Alternatives
+
+- **Alternative A** (chosen): reasons for the choice...
+- **Alternative B**: reasons for not choosing...
+- **Alternative C**: pros and cons...
+
+## Consequences
+
+Explain the impacts of this decision:
+
+- Immediate or long-term benefits
+- Possible risks or side effects
+- Actions required to implement the decision
+
+## Relations
+
+- Replaces ADR: 'adr-XXXX.md' _(if applicable)_
+- Replaced by ADR: 'adr-XXXX.md' _(if applicable)_
+- Related to: issues, RFCs, previous decisions
+
+---
+
+_This ADR follows the model of [Joel Parker Henderson](https://github.com/joelparkerhenderson/architecture-decision-record)_
+`
+
 func toKebabCase(s string) string {
+	if smartSlug {
+		s = splitCamelCase(s)
+	}
 	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "-")
-	s = strings.ReplaceAll(s, "_", "-")
+	s = strings.ReplaceAll(s, " ", separator)
+	for _, sep := range []string{"-", "_"} {
+		if sep != separator {
+			s = strings.ReplaceAll(s, sep, separator)
+		}
+	}
 	return s
 }
 
-func ensureDir(path string) error {
-	return os.MkdirAll(path, os.ModePerm)
+// splitCamelCase puts a space at camelCase boundaries, keeping acronyms
+// together: "HTTPServerChoice" becomes "HTTP Server Choice". A lone
+// trailing "s" is read as a plural acronym ("APIs"), not a new word.
+func splitCamelCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if nextLower && runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2])) {
+				nextLower = false
+			}
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
 
-func writeFile(path, content string) error {
-	return os.WriteFile(path, []byte(content), 0644)
+func isTemplateFile(name string) bool {
+	return strings.HasPrefix(name, "template") && strings.HasSuffix(name, ".md")
 }
 
-func extractTitleFromFilename(filename string) string {
-	name := strings.TrimSuffix(filename, ".md")
-	parts := strings.SplitN(name, "-", 2)
-	if len(parts) < 2 {
-		return filename
+// isIndexFile reports whether name is the index, a filtered index written
+// with --index-file, or a README-<suffix>.md from an earlier filtered run.
+func isIndexFile(name string) bool {
+	base := strings.TrimSuffix(indexFile, filepath.Ext(indexFile))
+	return name == indexFile || name == indexFileName || strings.HasPrefix(name, base+"-")
+}
+
+func isADRFile(name string) bool {
+	if _, ignored := ignoredBy(name); ignored {
+		return false
 	}
-	return strings.ReplaceAll(cases.Title(language.English).String(strings.ReplaceAll(parts[1], "-", " ")), "Adr ", "ADR ")
+	return strings.HasSuffix(name, ".md") && !isIndexFile(name) && !isTemplateFile(name) && !isCompanionFile(name)
 }
 
-func updateIndex() error {
-	files, err := os.ReadDir(adrDir)
+// looksLikeProjectRoot reports whether dir seems to be a repository root
+// rather than a dedicated ADR directory, along with the reason.
+func looksLikeProjectRoot(dir string) (bool, string) {
+	for _, marker := range []string{"go.mod", ".git"} {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true, fmt.Sprintf("it contains %s", marker)
+		}
+	}
+
+	files, err := os.ReadDir(dir)
 	if err != nil {
-		return err
+		return false, ""
 	}
 
-	var adrs []string
+	var others []string
 	for _, file := range files {
-		if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") || file.Name() == indexFile || file.Name() == templateFile {
+		if file.IsDir() || !isADRFile(file.Name()) {
 			continue
 		}
-		adrs = append(adrs, file.Name())
+		if _, ok := parseADRNumber(file.Name()); !ok {
+			others = append(others, file.Name())
+		}
+	}
+	if len(others) >= 3 {
+		return true, fmt.Sprintf("it contains %d Markdown files that are not ADRs (%s, ...)", len(others), strings.Join(others[:3], ", "))
 	}
+	return false, ""
+}
 
-	sort.Strings(adrs)
+func ensureDir(path string) error {
+	return fsys.MkdirAll(path)
+}
 
-	indexPath := filepath.Join(adrDir, indexFile)
-	indexContent := "# 📄 Architecture Decision Records\n\n"
+// resolveSymlink returns the file a symlink points to, even when the target
+// doesn't exist yet, so writes go through the link instead of replacing it.
+func resolveSymlink(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return path, nil
+	}
 
-	for _, adr := range adrs {
-		title := extractTitleFromFilename(adr)
-		indexContent += fmt.Sprintf("- [%s](%s)\n", title, adr)
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		return target, nil
 	}
 
-	return os.WriteFile(indexPath, []byte(indexContent), 0644)
+	// Dangling link: write where it points
+	target, err := os.Readlink(path)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return target, nil
 }
 
-func loadTemplateOrDefault() string {
-	path := filepath.Join(adrDir, templateFile)
-	bytes, err := os.ReadFile(path)
-	if err == nil {
-		return string(bytes)
+func writeFile(path, content string) error {
+	if err := fsys.WriteFile(path, []byte(content)); err != nil {
+		return err
 	}
+	debugf("wrote %s (%d bytes)", path, len(content))
+	return nil
+}
 
-	// Default embedded template
-	return `# ADR {{number}}: {{title}}
+// writeFileIfChanged skips the write when path already holds content, so
+// re-runs don't touch files. It reports whether the file was written.
+func writeFileIfChanged(path, content string) (bool, error) {
+	if existing, err := fsys.ReadFile(path); err == nil && string(existing) == content {
+		debugf("skipped writing %s: content unchanged", path)
+		return false, nil
+	}
+	return true, writeFile(path, content)
+}
 
-**Status**: {{status}}  
-**Date**: {{date}}
+func isTransientError(err error) bool {
+	transient := []error{syscall.EAGAIN, syscall.EINTR, syscall.EBUSY, syscall.ETIMEDOUT, syscall.ESTALE, syscall.EIO}
+	for _, target := range transient {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
 
----
+func withRetry(op func() error) error {
+	delay := retryDelay
+	var err error
+	for attempt := 1; attempt <= retryAttempts; attempt++ {
+		err = op()
+		if err == nil || !isTransientError(err) {
+			return err
+		}
+		if attempt < retryAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return err
+}
 
-## Context
+func readDirWithRetry(path string) ([]os.DirEntry, error) {
+	var files []os.DirEntry
+	err := withRetry(func() error {
+		var err error
+		files, err = fsys.ReadDir(path)
+		return err
+	})
+	return files, err
+}
 
-Describe here the problem, need, or motivation for this decision. Include the current scenario, technical or business constraints, and the factors influencing the choice.
+func readFileWithRetry(path string) ([]byte, error) {
+	var content []byte
+	err := withRetry(func() error {
+		var err error
+		content, err = fsys.ReadFile(path)
+		return err
+	})
+	return content, err
+}
 
-## Decision
+// extractTitleFromFilename turns the slug of an ADR filename back into a
+// title. Filenames that don't match the filename template are read as
+// NNN-slug.md or adr-NNN-slug.md.
+func extractTitleFromFilename(filename string) string {
+	var slug string
+	if fields := matchFilenameFields(filename); fields != nil && fields["slug"] != "" {
+		slug = fields["slug"]
+	} else if _, _, rest, ok := categorizedADR(filename); ok {
+		slug = rest
+	} else {
+		name := strings.TrimSuffix(filename, ".md")
+		if rest, ok := strings.CutPrefix(name, "adr"+separator); ok {
+			name = rest
+		}
+		number, rest, ok := strings.Cut(name, separator)
+		if _, valid := parseNumber(number); !ok || !valid || rest == "" {
+			return filename
+		}
+		slug = rest
+	}
 
-Clearly state the decision made. For example:
+	words := strings.Split(cases.Title(language.English).String(strings.ReplaceAll(slug, separator, " ")), " ")
+	for i, word := range words {
+		if word == "Adr" {
+			words[i] = "ADR"
+		}
+	}
+	return strings.Join(words, " ")
+}
 
-> We decided to adopt the XYZ framework for developing REST APIs in the ABC project.
+func parseADRNumber(filename string) (int, bool) {
+	if num, ok := matchADRFilename(filename); ok {
+		return num, true
+	}
 
-## Considered Alternatives
+	// Accept both adr-XXX-*.md and XXX-*.md
+	name := strings.TrimPrefix(filename, "adr"+separator)
+	return parseNumber(strings.SplitN(name, separator, 2)[0])
+}
 
-- **Alternative A** (chosen): reasons for the choice...
-- **Alternative B**: reasons for not choosing...
-- **Alternative C**: pros and cons...
+func sortADRFiles(adrs []string) {
+	sort.SliceStable(adrs, func(i, j int) bool {
+		numI, okI := parseADRNumber(adrs[i])
+		numJ, okJ := parseADRNumber(adrs[j])
+		if okI && okJ && numI != numJ {
+			return numI < numJ
+		}
+		if okI != okJ {
+			return okI // Numbered ADRs come before anything else
+		}
+		return adrs[i] < adrs[j]
+	})
+}
 
-## Consequences
+func normalizeStatus(status string) (string, bool) {
+	for _, known := range statuses {
+		if strings.EqualFold(status, known) {
+			return known, true
+		}
+	}
+	return status, false
+}
 
-Explain the impacts of this decision:
+func loadTemplateOrDefault() string {
+	if isRemoteURL(templateName) {
+		return loadRemoteTemplate(templateName)
+	}
+	path := templatePath(templateName)
+	bytes, err := fsys.ReadFile(path)
+	if err == nil {
+		return string(bytes)
+	}
 
-- Immediate or long-term benefits
-- Possible risks or side effects
-- Actions required to implement the decision
+	return defaultTemplate
+}
 
-## Relations
+// templateVars collects repeated --template-var key=value flags.
+type templateVars map[string]string
 
-- Replaces ADR: 'adr-XXXX.md' _(if applicable)_
-- Replaced by ADR: 'adr-XXXX.md' _(if applicable)_
-- Related to: issues, RFCs, previous decisions
+func (v *templateVars) String() string {
+	var pairs []string
+	for key, value := range *v {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
 
----
+func (v *templateVars) Set(pair string) error {
+	key, value, ok := strings.Cut(pair, "=")
+	key = strings.TrimSpace(key)
+	if !ok || !placeholderKeyPattern.MatchString(key) {
+		return fmt.Errorf("expected key=value with a key of letters, digits, '_', '.' or '-'")
+	}
+	if isReservedPlaceholder(key) {
+		return fmt.Errorf("{{%s}} is set by adrgen and can't be overridden", key)
+	}
+	if *v == nil {
+		*v = make(templateVars)
+	}
+	(*v)[key] = value
+	return nil
+}
 
-_This ADR follows the model of [Joel Parker Henderson](https://github.com/joelparkerhenderson/architecture-decision-record)_
-`
+// unresolvedPlaceholders lists the {{key}} placeholders left in rendered
+// content, each once.
+func unresolvedPlaceholders(content string) []string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, match := range placeholderPattern.FindAllString(content, -1) {
+		if !seen[match] {
+			seen[match] = true
+			missing = append(missing, match)
+		}
+	}
+	return missing
 }
 
 func renderTemplate(template, number, status, title, date string) string {
-	replacer := strings.NewReplacer(
-		"{{number}}", number,
-		"{{status}}", status,
-		"{{title}}", title,
-		"{{date}}", date,
-	)
-	return replacer.Replace(template)
+	return renderTemplateVars(template, map[string]string{
+		"number": number,
+		"status": status,
+		"title":  title,
+		"date":   date,
+	})
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{([A-Za-z0-9_.-]+)\}\}`)
+var placeholderKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// placeholderResolver supplies values for placeholders missing from the
+// static map. Returning false leaves the placeholder untouched.
+type placeholderResolver func(key string) (string, bool)
+
+func renderTemplateVars(template string, vars map[string]string) string {
+	return renderTemplateWith(template, vars, nil)
+}
+
+// renderTemplateWith fills {{key}} placeholders from vars, consulting
+// resolve for keys that vars doesn't have.
+func renderTemplateWith(template string, vars map[string]string, resolve placeholderResolver) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		key := placeholder[2 : len(placeholder)-2]
+		if value, ok := vars[key]; ok {
+			return value
+		}
+		if resolve != nil {
+			if value, ok := resolve(key); ok {
+				return value
+			}
+		}
+		return placeholder
+	})
 }
 
 func adrExists(number string) bool {
-	files, err := os.ReadDir(adrDir)
+	files, err := fsys.ReadDir(adrDir)
 	if err != nil {
 		return false
 	}
 
-	prefix := fmt.Sprintf("adr-%s-", number)
-	for _, file := range files {
-		if strings.HasPrefix(file.Name(), prefix) {
-			return true
-		}
-	}
-	return false
+	_, found := findADRFile(files, number)
+	return found
 }
 
+// getNextADRNumber returns the number after the highest existing ADR, or with
+// --fill-gaps the lowest unused one. Reserved numbers are never returned.
 func getNextADRNumber() string {
-	files, err := os.ReadDir(adrDir)
-	if err != nil {
-		return "001" // Start with 001 if directory doesn't exist
-	}
+	// A missing directory starts at 001. Archived ADRs keep their numbers.
+	files, _ := fsys.ReadDir(adrDir)
+	archived, _ := fsys.ReadDir(filepath.Join(adrDir, archiveDir))
+	files = append(files, archived...)
 
+	debugf("scanning %s for the next ADR number", adrDir)
+	used := make(map[int]bool)
 	maxNum := 0
 	for _, file := range files {
-		if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") || file.Name() == indexFile || file.Name() == templateFile {
+		if file.IsDir() {
+			continue
+		}
+		if pattern, ok := ignoredBy(file.Name()); ok {
+			debugf("skipped %s: matches ignore pattern %q", file.Name(), pattern)
+			continue
+		}
+		if !isADRFile(file.Name()) {
 			continue
 		}
 
-		// Extract number from filename (format: adr-XXX-*.md)
-		if strings.HasPrefix(file.Name(), "adr-") {
-			numStr := strings.Split(strings.TrimPrefix(file.Name(), "adr-"), "-")[0]
-			if num, err := strconv.Atoi(numStr); err == nil {
-				if num > maxNum {
-					maxNum = num
-				}
-			}
+		// Extract number from filename (format given by the filename template)
+		num, ok := matchADRFilename(file.Name())
+		if !ok {
+			debugf("skipped %s: does not match filename template %q", file.Name(), activeFilenameTemplate())
+			continue
+		}
+		used[num] = true
+		if num > maxNum {
+			maxNum = num
 		}
 	}
 
-	return fmt.Sprintf("%03d", maxNum+1)
-}
-
-func promptForNumber() (string, error) {
-	nextNum := getNextADRNumber()
+	// Numbers claimed on unmerged branches are skipped
+	reserved, err := reservedNumbers()
+	if err != nil {
+		reserved = map[int]bool{}
+	}
 
-	validate := func(input string) error {
-		if len(input) == 0 {
-			return fmt.Errorf("number cannot be empty")
-		}
-		if len(input) != 3 {
-			return fmt.Errorf("number must be 3 digits (e.g., 001)")
+	// Numbering starts at --base-number and never goes below it
+	next := max(maxNum+1, baseNumber)
+	if fillGaps {
+		next = baseNumber
+	}
+	for used[next] || reserved[next] {
+		if reserved[next] {
+			debugf("skipped number %s: reserved in %s", formatNumber(next), reservedFile)
 		}
-		if _, err := strconv.Atoi(input); err != nil {
-			return fmt.Errorf("number must be numeric")
+		next++
+	}
+	debugf("allocated number %s (highest existing: %d)", formatNumber(next), maxNum)
+	return formatNumber(next)
+}
+
+func validateNumber(input string) error {
+	if len(input) == 0 {
+		return fmt.Errorf("number cannot be empty")
+	}
+	if numberFormat != "padded" {
+		if num, ok := parseNumber(input); !ok || formatNumber(num) != input {
+			return fmt.Errorf("number must be a %s number (e.g., %s)", numberFormat, formatNumber(1))
 		}
 		return nil
 	}
+	if len(input) != numberWidth {
+		return fmt.Errorf("number must be %d digits (e.g., %s)", numberWidth, formatNumber(1))
+	}
+	if _, err := strconv.Atoi(input); err != nil {
+		return fmt.Errorf("number must be numeric")
+	}
+	return nil
+}
+
+func promptForNumber() (string, error) {
+	nextNum := getNextADRNumber()
 
 	prompt := promptui.Prompt{
 		Label:     "ADR Number",
-		Validate:  validate,
+		Validate:  validateNumber,
 		Default:   nextNum,
 		AllowEdit: true,
+		Templates: promptTemplates(),
 	}
 
 	return prompt.Run()
 }
 
+// promptForStatus asks for a status with the --default-status preselected.
 func promptForStatus() (string, error) {
 	prompt := promptui.Select{
-		Label: "Select Status",
-		Items: []string{"Accepted", "Proposed", "Rejected", "Superseded", "Deprecated"},
+		Label:     "Select Status",
+		Items:     statuses,
+		CursorPos: max(slices.Index(statuses, defaultStatus), 0),
+		Templates: selectTemplates(),
 	}
 
 	_, result, err := prompt.Run()
@@ -218,93 +555,146 @@ func getCurrentTitle(content string) string {
 			}
 		}
 	}
+
+	// Legacy files without an ADR heading
+	if title, ok := findField(lines, "Title"); ok {
+		return title.Value
+	}
 	return ""
 }
 
-func updateTitle(content, newTitle string) string {
+// updateTitle sets the title in the "# ADR N:" heading, or the legacy Title
+// field. Files with neither get a heading for number at the top, after any
+// frontmatter.
+func updateTitle(content, number, newTitle string) string {
 	lines := strings.Split(content, "\n")
 	for i, line := range lines {
 		if strings.HasPrefix(line, "# ADR") {
 			parts := strings.SplitN(line, ": ", 2)
 			if len(parts) == 2 {
 				lines[i] = fmt.Sprintf("%s: %s", parts[0], newTitle)
+				return strings.Join(lines, "\n")
+			}
+		}
+	}
+
+	if title, ok := findField(lines, "Title"); ok {
+		if title.Style == fieldSection {
+			lines[title.End] = newTitle
+		} else {
+			lines[title.Line] = strings.TrimRight(formatField(title.Style, "Title", newTitle)[0], " ")
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	insertAt := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				insertAt = i + 1
 				break
 			}
 		}
 	}
-	return strings.Join(lines, "\n")
+	rest := lines[insertAt:]
+	for len(rest) > 0 && strings.TrimSpace(rest[0]) == "" {
+		rest = rest[1:]
+	}
+	heading := []string{fmt.Sprintf("# ADR %s: %s", number, newTitle), ""}
+	if insertAt > 0 {
+		heading = append([]string{""}, heading...)
+	}
+	return strings.Join(append(append(lines[:insertAt:insertAt], heading...), rest...), "\n")
 }
 
-func promptForTitle(defaultTitle string) (string, error) {
-	validate := func(input string) error {
-		if len(input) == 0 {
-			return fmt.Errorf("title cannot be empty")
-		}
-		return nil
+func validateTitle(input string) error {
+	if len(input) == 0 {
+		return fmt.Errorf("title cannot be empty")
+	}
+	if length := utf8.RuneCountInString(input); maxTitleLength > 0 && length > maxTitleLength {
+		return fmt.Errorf("title is %d characters long, the maximum is %d", length, maxTitleLength)
 	}
+	return nil
+}
 
+func promptForTitle(defaultTitle string) (string, error) {
 	prompt := promptui.Prompt{
 		Label:     "ADR Title",
-		Validate:  validate,
+		Validate:  validateTitle,
 		Default:   defaultTitle,
 		AllowEdit: true,
+		Templates: promptTemplates(),
 	}
 
 	return prompt.Run()
 }
 
+// getCurrentStatus reads the Status field in the header region, before the
+// first section, so a stray "**Status**:" further down can't shadow it.
+// Files with no status there fall back to the first one anywhere.
 func getCurrentStatus(content string) string {
 	lines := strings.Split(content, "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "**Status**: ") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "**Status**: "))
-		}
+	if status, ok := headerField(lines, "Status"); ok {
+		return canonicalStatus(status.Value)
+	}
+	if status, ok := findField(lines, "Status"); ok {
+		return canonicalStatus(status.Value)
 	}
 	return ""
 }
 
+// supersededStatusPattern matches statuses that name their successor, such
+// as "Superseded by ADR-012" from --superseded-label.
+var supersededStatusPattern = regexp.MustCompile(`^(?i:superseded)\s+by\b`)
+
+// canonicalStatus reads a status naming its successor as Superseded, so
+// grouping and filters see one status.
+func canonicalStatus(status string) string {
+	if supersededStatusPattern.MatchString(strings.TrimSpace(status)) {
+		return "Superseded"
+	}
+	return status
+}
+
+// updateStatus sets the status and records the old one as Previous Status,
+// in the style (bold, plain or section) the file already uses. With
+// --no-previous-status only the status is written.
 func updateStatus(content, newStatus string) string {
 	currentStatus := getCurrentStatus(content)
 	if currentStatus == newStatus {
 		return content // Status hasn't changed, return content as is
 	}
 
-	lines := strings.Split(content, "\n")
-	newLines := make([]string, 0, len(lines))
-	statusFound := false
-	dateFound := false
+	newLines := removeFields(strings.Split(content, "\n"), "Previous Status")
 
-	for _, line := range lines {
-		if strings.HasPrefix(line, "**Status**: ") {
-			if !statusFound {
-				// Add new status line and previous status line only once
-				newLines = append(newLines, fmt.Sprintf("**Status**: %s  ", newStatus))
-				newLines = append(newLines, fmt.Sprintf("**Previous Status**: %s  ", currentStatus))
-				statusFound = true
-			}
-			continue
+	// Keep only the first Status and Date fields
+	for _, name := range []string{"Status", "Date"} {
+		fields := findFields(newLines, name)
+		for i := len(fields) - 1; i > 0; i-- {
+			newLines = replaceLines(newLines, fields[i].Line, fields[i].End, nil)
 		}
+	}
 
-		// Skip any existing Previous Status lines
-		if strings.HasPrefix(line, "**Previous Status**: ") {
-			continue
+	status, statusFound := findField(newLines, "Status")
+	if statusFound {
+		replacement := formatField(status.Style, "Status", newStatus)
+		if status.Style == fieldSection {
+			replacement = append(replacement, "")
 		}
-
-		// Keep the date line in its original position
-		if strings.HasPrefix(line, "**Date**: ") {
-			if !dateFound {
-				newLines = append(newLines, line)
-				dateFound = true
-			}
-			continue
+		if !noPreviousStatus {
+			replacement = append(replacement, formatField(status.Style, "Previous Status", currentStatus)...)
+		} else if status.Style == fieldSection {
+			replacement = replacement[:len(replacement)-1]
 		}
-
-		// Add all other lines
-		newLines = append(newLines, line)
+		newLines = replaceLines(newLines, status.Line, status.End, replacement)
 	}
 
 	// If we haven't found and added the status yet, add it after the title
 	if !statusFound {
+		statusLines := []string{fmt.Sprintf("**Status**: %s", newStatus)}
+		if !noPreviousStatus {
+			statusLines = append(statusLines, fmt.Sprintf("**Previous Status**: %s", currentStatus))
+		}
 		result := make([]string, 0, len(newLines)+2)
 		titleFound := false
 		for _, line := range newLines {
@@ -312,17 +702,12 @@ func updateStatus(content, newStatus string) string {
 			if strings.HasPrefix(line, "# ADR") {
 				titleFound = true
 				result = append(result, "")
-				result = append(result, fmt.Sprintf("**Status**: %s", newStatus))
-				result = append(result, fmt.Sprintf("**Previous Status**: %s", currentStatus))
+				result = append(result, statusLines...)
 			}
 		}
 		if !titleFound {
 			// If no title was found, add status at the beginning
-			result = append([]string{
-				fmt.Sprintf("**Status**: %s", newStatus),
-				fmt.Sprintf("**Previous Status**: %s", currentStatus),
-				"",
-			}, result...)
+			result = append(append([]string{}, statusLines...), append([]string{""}, result...)...)
 		}
 		newLines = result
 	}
@@ -330,113 +715,908 @@ func updateStatus(content, newStatus string) string {
 	return strings.Join(newLines, "\n")
 }
 
-func main() {
-	number, err := promptForNumber()
+func openCommand(path string) *exec.Cmd {
+	var name string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		name, args = "open", []string{path}
+	case "windows":
+		name, args = "rundll32", []string{"url.dll,FileProtocolHandler", path}
+	default:
+		// Without a display there's nothing to open the file with
+		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			return nil
+		}
+		name, args = "xdg-open", []string{path}
+	}
+
+	if _, err := exec.LookPath(name); err != nil {
+		return nil
+	}
+	return exec.Command(name, args...)
+}
+
+func openFile(path string) error {
+	cmd := openCommand(path)
+	if cmd == nil {
+		return nil
+	}
+	return cmd.Start()
+}
+
+type createOptions struct {
+	number         string
+	status         string
+	title          string
+	openIndex      bool
+	forceOverwrite bool
+	forceNew       bool
+	allowRoot      bool
+	stampGit       bool
+	count          int
+	wrap           int
+	canonicalize   bool
+	note           string
+	keepFilename   bool
+	selectADR      bool
+	templateVars   templateVars
+	drivers        stringList
+	meta           stringList
+	amends         string
+	from           string
+	dryRun         bool
+	planFormat     string
+	inputJSON      string
+	date           string
+	datetime       bool
+	timestamp      string
+	author         string
+	tags           []string
+}
+
+// registerDirFlags registers the flags that control where ADRs live, how
+// their filenames look and how they are numbered, plus --quiet, shared by
+// every command.
+func registerDirFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&quiet, "quiet", quiet, "Don't show progress while reading the ADR directory")
+	fs.BoolVar(&verbose, "verbose", verbose, "Log each step (files scanned and skipped, numbers allocated, files written) to stderr")
+	fs.StringVar(&adrDir, "dir", adrDir, "Directory containing the ADRs")
+	fs.BoolVar(&noDiscovery, "no-discovery", noDiscovery, "Don't look for an existing ADR directory or .adrgen.yaml in parent directories")
+	fs.StringVar(&separator, "separator", separator, "Separator between the prefix, number and title words in filenames")
+	fs.StringVar(&filenameTemplate, "filename-template", filenameTemplate, "Pattern for ADR filenames with {{number}}, {{slug}}, {{date}}, {{year}} and {{type}} (default adr-{{number}}-{{slug}}.md)")
+	fs.StringVar(&category, "category", category, "Category prefix with its own number sequence (e.g., SEC for adr-SEC-001-...)")
+	fs.BoolVar(&smartSlug, "smart-slug", smartSlug, "Split camelCase and PascalCase titles into words in filenames (DatabaseChoice becomes database-choice)")
+	fs.IntVar(&numberWidth, "number-width", numberWidth, "Number of digits ADR numbers are zero-padded to")
+	fs.StringVar(&numberFormat, "number-format", numberFormat, "How ADR numbers are written: padded (zero-padded to --number-width), decimal or roman")
+	fs.BoolVar(&fillGaps, "fill-gaps", fillGaps, "Allocate the lowest unused number instead of the one after the highest")
+	fs.IntVar(&baseNumber, "base-number", baseNumber, "Lowest number allocated to new ADRs, e.g. 100 to keep imported ADRs in their own range")
+	fs.StringVar(&ignorePatterns, "ignore", ignorePatterns, "Comma-separated filename globs that are never treated as ADRs (editor backups and temp files by default)")
+}
+
+// registerIndexFlags registers the flags that shape the generated index.
+func registerIndexFlags(fs *flag.FlagSet) {
+	fs.StringVar(&indexGroupBy, "group-by", indexGroupBy, "Group the index by status, month or year instead of a flat list")
+	fs.StringVar(&indexStatusSections, "status-sections", indexStatusSections, "Order and visibility of the status groups, e.g. \"Accepted,Proposed,Superseded=collapsed,Rejected=hidden\" (implies --group-by status)")
+	fs.StringVar(&indexSort, "sort", indexSort, "Order of the index: name (filename order, the default) or date (newest first)")
+	fs.StringVar(&indexTitleCase, "title-case", indexTitleCase, "Title-case index titles derived from filenames (on), or use each ADR's # ADR N: heading as written (off)")
+	fs.BoolVar(&indexReverse, "reverse", indexReverse, "Reverse the index order (oldest first when sorting by date)")
+	fs.StringVar(&indexFormat, "index-format", indexFormat, "Format of the generated index: markdown, confluence or tasklist")
+	fs.StringVar(&taskListDone, "tasklist-done", taskListDone, "Comma-separated statuses checked off in the tasklist index format")
+	fs.StringVar(&indexSince, "since", indexSince, "Only list ADRs dated on or after this day (YYYY-MM-DD) in the index")
+	fs.StringVar(&indexUntil, "until", indexUntil, "Only list ADRs dated on or before this day (YYYY-MM-DD) in the index")
+	fs.StringVar(&indexFileName, "index-file", indexFileName, "Write the index to this file in the ADR directory instead of README.md (e.g., README-2024.md)")
+	fs.BoolVar(&indexWithSummary, "with-summary", indexWithSummary, "Show each ADR's one-line summary next to its title in the index")
+	fs.StringVar(&indexHeader, "index-header", indexHeader, "File in the ADR directory, or an http(s) URL, whose content replaces the index heading")
+	fs.StringVar(&indexHeading, "index-heading", indexHeading, "Title of the index heading, in place of \"📄 Architecture Decision Records\"")
+	fs.StringVar(&indexEmptyMessage, "empty-index-message", indexEmptyMessage, "Line shown in the index when there are no ADRs (empty for none)")
+	fs.BoolVar(&indexIcons, "icons", indexIcons, "Prefix index entries with a status icon (✅ Accepted, 🕓 Proposed, ...)")
+	fs.StringVar(&indexIconMap, "icon-map", indexIconMap, "Override status icons for --icons (e.g., \"Proposed=📝,Parked=⏸️\")")
+	fs.StringVar(&indexEntryTemplate, "entry-template", indexEntryTemplate, "Format of each index line with {{number}}, {{title}}, {{status}}, {{date}}, {{file}} and {{icon}} (default \"- [{{title}}]({{file}})\")")
+}
+
+func registerFlags(fs *flag.FlagSet, opts *createOptions) {
+	fs.StringVar(&opts.number, "number", "", "Sequential ADR number (e.g., 001); prompted for when omitted")
+	fs.StringVar(&opts.status, "status", "", "Decision status (e.g., Accepted); prompted for when omitted")
+	fs.StringVar(&defaultStatus, "default-status", defaultStatus, "Status preselected in the status prompt and used for --count placeholders (e.g., Proposed)")
+	fs.StringVar(&opts.title, "title", "", "Descriptive title for the ADR; prompted for when omitted")
+	fs.BoolVar(&opts.openIndex, "open-index", false, "Open the generated index after a successful run")
+	fs.IntVar(&maxTitleLength, "max-title-length", maxTitleLength, "Reject titles longer than this many characters (0 means no limit)")
+	fs.IntVar(&opts.count, "count", 1, "Number of sequential placeholder ADRs to create (e.g., to reserve a block)")
+	fs.IntVar(&opts.wrap, "wrap", 0, "Hard-wrap prose in new ADRs at this column (0 disables wrapping)")
+	fs.BoolVar(&opts.keepFilename, "keep-filename", false, "When retitling an ADR, update its heading but keep the filename so existing links stay valid")
+	fs.BoolVar(&noPreviousStatus, "no-previous-status", noPreviousStatus, "Update the status in place without recording a Previous Status field")
+	fs.StringVar(&preWriteHook, "pre-write-hook", preWriteHook, "Shell command run before an ADR is written, with the file path as $1 and the content on stdin; a non-zero exit cancels the write")
+	fs.StringVar(&templateEngine, "template-engine", templateEngine, "How templates are rendered: simple ({{key}} placeholders) or gotemplate (Go text/template); detected from the template when not set")
+	fs.Var(&opts.templateVars, "template-var", "Value for a custom template placeholder as key=value, e.g. ticket=ARCH-42 for {{ticket}} (repeatable)")
+	fs.Var(&opts.drivers, "driver", "Decision driver listed under the Decision Drivers section of a new ADR (repeatable)")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "Print the files that would be created, updated or removed without changing anything")
+	fs.StringVar(&opts.planFormat, "format", "text", "Output format of --dry-run: text or json")
+	fs.StringVar(&opts.from, "from", "", "Number of an existing ADR whose content the new one starts from instead of the template (its relations and Decision Log are left out)")
+	fs.StringVar(&opts.amends, "amends", "", "Number of an ADR the new one partially revises: adds an Amends relation to it and an Amended by relation to that ADR, whose status is kept")
+	fs.Var(&opts.meta, "meta", "Set a Key: Value line in the ADR's metadata footer, as Key=Value (repeatable)")
+	fs.BoolVar(&opts.selectADR, "update", false, "Pick the ADR to update from a searchable list instead of entering a number")
+	fs.StringVar(&opts.inputJSON, "input-json", "", "Read number, status, title, date, author and tags from a JSON file, or stdin with -")
+	fs.StringVar(&opts.note, "note", "", "Rationale for the status change, appended with the date to the ADR's Decision Log section")
+	fs.BoolVar(&opts.canonicalize, "canonicalize", false, "When updating, convert legacy Title:/Status:/## Status fields to the canonical **Field**: format")
+	fs.BoolVar(&opts.datetime, "datetime", false, "Also record the creation time as an RFC3339 **Timestamp** field (e.g., 2024-06-01T14:30:00-03:00) next to the Date")
+	fs.BoolVar(&opts.stampGit, "stamp-git", false, "Record the git author and current commit in new ADRs ({{author}} and {{commit}})")
+	fs.BoolVar(&opts.forceOverwrite, "force-overwrite", false, "Replace an existing file when a new ADR's filename is already taken")
+	fs.BoolVar(&opts.forceNew, "force-new", false, "Create a new ADR for --number even if one exists (with --force-overwrite the existing file is replaced)")
+	registerDirFlags(fs)
+	fs.BoolVar(&opts.allowRoot, "allow-root", false, "Allow using a directory that looks like a project root as the ADR directory")
+	fs.StringVar(&adrType, "type", adrType, "Value of the {{type}} placeholder in the filename template")
+	fs.StringVar(&templateName, "template", templateName, "Name of the template to use (template-<name>.md in the ADR directory), or an http(s) URL to fetch it from")
+	registerIndexFlags(fs)
+	fs.IntVar(&retryAttempts, "retries", retryAttempts, "Number of attempts for filesystem operations that fail with transient errors")
+	fs.DurationVar(&retryDelay, "retry-delay", retryDelay, "Delay before the first retry, doubled on each further attempt")
+}
+
+func newADRContent(number, status, title, date string, opts createOptions) (string, error) {
+	template, err := expandIncludes(loadTemplateOrDefault())
 	if err != nil {
-		fmt.Printf("Prompt failed %v\n", err)
-		return
+		return "", err
+	}
+	vars := map[string]string{
+		"number":   number,
+		"status":   status,
+		"title":    title,
+		"date":     date,
+		"category": category,
+	}
+	if opts.timestamp != "" {
+		vars["timestamp"] = opts.timestamp
+	}
+	if opts.stampGit {
+		author, commit, err := gitStamp(adrDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not read git metadata: %v\n", err)
+		}
+		if opts.author != "" {
+			author = opts.author
+		}
+		vars["author"], vars["commit"] = author, commit
+		template = withGitFooter(template, author, commit)
+	}
+	if opts.author != "" {
+		vars["author"] = opts.author
+	}
+	if len(opts.tags) > 0 {
+		vars["tags"] = strings.Join(opts.tags, ", ")
+	}
+	for key, value := range opts.templateVars {
+		vars[key] = value
+	}
+	rendered, err := renderADRTemplate(template, vars)
+	if err != nil {
+		return "", err
+	}
+	content := withDrivers(withPayloadFields(rendered, template, opts), opts.drivers)
+	if usesGoTemplate(template) {
+		return wrapMarkdown(content, opts.wrap), nil
 	}
 
-	status, err := promptForStatus()
+	// Companion templates may be what uses a --template-var
+	if declared := templatePlaceholders(template); len(companions) == 0 {
+		for _, key := range slices.Sorted(maps.Keys(opts.templateVars)) {
+			if !slices.Contains(declared, key) {
+				fmt.Fprintf(os.Stderr, "Warning: The template has no {{%s}} placeholder for --template-var %s\n", key, key)
+			}
+		}
+	}
+	if missing := missingPlaceholders(template, vars); len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: No value for template placeholder(s) %s (set them with --template-var key=value)\n", strings.Join(missing, ", "))
+	}
+	return wrapMarkdown(content, opts.wrap), nil
+}
+
+// createADRBlock writes count sequential placeholder ADRs starting at
+// opts.number (or the next free number) and returns their paths. Nothing is
+// written when any number in the block is already taken.
+func createADRBlock(count int, opts createOptions) ([]string, error) {
+	start := opts.number
+	if start == "" {
+		start = getNextADRNumber()
+	}
+	first, ok := parseNumber(start)
+	if !ok {
+		return nil, fmt.Errorf("invalid ADR number %q", start)
+	}
+	if last := formatNumber(first + count - 1); numberFormat == "padded" && len(last) > numberWidth {
+		return nil, fmt.Errorf("a block of %d starting at %s exceeds %d-digit numbers", count, start, numberWidth)
+	}
+
+	status := opts.status
+	if status == "" {
+		status = cmp.Or(defaultStatus, "Proposed")
+	}
+
+	reserved, err := reservedNumbers()
 	if err != nil {
-		fmt.Printf("Prompt failed %v\n", err)
+		return nil, err
+	}
+
+	type placeholder struct{ number, title string }
+	var block []placeholder
+	for i := 0; i < count; i++ {
+		number := formatNumber(first + i)
+		if adrExists(number) {
+			return nil, fmt.Errorf("ADR %s already exists", number)
+		}
+		if reserved[first+i] {
+			return nil, fmt.Errorf("ADR %s is reserved in %s", number, reservedFile)
+		}
+		title := "TBD"
+		if opts.title != "" {
+			title = fmt.Sprintf("%s %d", opts.title, i+1)
+		}
+		block = append(block, placeholder{number, title})
+	}
+
+	// Render and check every ADR before writing the first one
+	date := time.Now().Format("2006-01-02")
+	contents := make([]string, len(block))
+	for i, p := range block {
+		content, err := newADRContent(p.number, status, p.title, date, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := runPreWriteHook(filepath.Join(adrDir, adrFilename(p.number, p.title)), content); err != nil {
+			return nil, err
+		}
+		contents[i] = content
+	}
+
+	var paths []string
+	for i, p := range block {
+		path := filepath.Join(adrDir, adrFilename(p.number, p.title))
+		if err := withRetry(func() error { return writeFile(path, contents[i]) }); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func runNextCommand(args []string) error {
+	fs := flag.NewFlagSet("next", flag.ContinueOnError)
+	registerDirFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if numberWidth < 1 {
+		return fmt.Errorf("invalid --number-width %d: must be at least 1", numberWidth)
+	}
+	if !slices.Contains(numberFormatNames, numberFormat) {
+		return fmt.Errorf("invalid --number-format %q (supported: %s)", numberFormat, strings.Join(numberFormatNames, ", "))
+	}
+	if baseNumber < 1 {
+		return fmt.Errorf("invalid --base-number %d: must be at least 1", baseNumber)
+	}
+
+	fmt.Println(getNextADRNumber())
+	return nil
+}
+
+func runCommand(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "template":
+		return true, runTemplateCommand(args[1:])
+	case "completion":
+		return true, runCompletionCommand(args[1:])
+	case "relate":
+		return true, runRelateCommand(args[1:])
+	case "sync-headings":
+		return true, runSyncHeadingsCommand(args[1:])
+	case "schema":
+		return true, runSchemaCommand(args[1:])
+	case "lint":
+		return true, runLintCommand(args[1:])
+	case "diff":
+		return true, runDiffCommand(args[1:])
+	case "export":
+		return true, runExportCommand(args[1:])
+	case "reserve":
+		return true, runReserveCommand(args[1:])
+	case "next":
+		return true, runNextCommand(args[1:])
+	case "index":
+		return true, runIndexCommand(args[1:])
+	case "archive":
+		return true, runArchiveCommand(args[1:])
+	case "list":
+		return true, runListCommand(args[1:])
+	case "graph":
+		return true, runGraphCommand(args[1:])
+	case "serve":
+		return true, runServeCommand(args[1:])
+	case "rollback":
+		return true, runRollbackCommand(args[1:])
+	case "digest":
+		return true, runDigestCommand(args[1:])
+	case "merge":
+		return true, runMergeCommand(args[1:])
+	case "move":
+		return true, runMoveCommand(args[1:])
+	case "import":
+		return true, runImportCommand(args[1:])
+	case "open":
+		return true, runOpenCommand(args[1:])
+	case "publish":
+		return true, runPublishCommand(args[1:])
+	case "validate-template":
+		return true, runValidateTemplateCommand(args[1:])
+	case "__complete":
+		return true, runCompleteCommand(args[1:])
+	}
+	return false, nil
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && (args[0] == "rollback" || args[0] == "__complete") {
+		if _, err := runCommand(args); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	err = ensureDir(adrDir)
+	// Record what this run changes so rollback can undo it
+	journal := startJournal(args)
+	code := run(args)
+	if err := journal.save(); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: Could not record the operation for rollback:", err)
+	}
+	os.Exit(code)
+}
+
+// run dispatches args to a subcommand or the create flow and returns the
+// process exit code.
+func run(args []string) int {
+	if handled, err := runCommand(args); handled {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	// "new" is an explicit name for the default create flow
+	if len(args) > 0 && args[0] == "new" {
+		args = args[1:]
+	}
+	return runCreate(args)
+}
+
+// runCreate runs the create/update flow and returns the process exit code.
+// Errors and warnings go to stderr; stdout only carries the results.
+func runCreate(args []string) int {
+	var opts createOptions
+	fs := flag.NewFlagSet("adrgen", flag.ContinueOnError)
+	registerFlags(fs, &opts)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	discoverADRDir(fs)
+
+	if adrDir == "" {
+		adrDir = "."
+	}
+	if isRoot, reason := looksLikeProjectRoot(adrDir); isRoot && !opts.allowRoot {
+		fmt.Fprintf(os.Stderr, "Refusing to use %q as the ADR directory because %s.\n", adrDir, reason)
+		fmt.Fprintf(os.Stderr, "The index would overwrite %s there and every Markdown file would be treated as an ADR.\n", indexFile)
+		fmt.Fprintln(os.Stderr, "Point --dir at your ADR directory (e.g. docs/adr) or pass --allow-root if this is intended.")
+		return 1
+	}
+
+	if separator == "" || strings.ContainsAny(separator, `/\`) {
+		fmt.Fprintf(os.Stderr, "Invalid --separator %q: must be non-empty and not contain path separators\n", separator)
+		return 1
+	}
+
+	if category != "" && !categoryPattern.MatchString(category) {
+		fmt.Fprintf(os.Stderr, "Invalid --category %q: must be letters and digits, starting with a letter\n", category)
+		return 1
+	}
+
+	if err := validateFilenameTemplate(activeFilenameTemplate()); err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid --filename-template:", err)
+		return 1
+	}
+
+	if numberWidth < 1 {
+		fmt.Fprintln(os.Stderr, "Invalid --number-width value: must be at least 1")
+		return 1
+	}
+	if !slices.Contains(numberFormatNames, numberFormat) {
+		fmt.Fprintf(os.Stderr, "Invalid --number-format %q (supported: %s)\n", numberFormat, strings.Join(numberFormatNames, ", "))
+		return 1
+	}
+	if baseNumber < 1 {
+		fmt.Fprintln(os.Stderr, "Invalid --base-number value: must be at least 1")
+		return 1
+	}
+
+	if retryAttempts < 1 {
+		fmt.Fprintln(os.Stderr, "Invalid --retries value: must be at least 1")
+		return 1
+	}
+
+	if defaultStatus != "" {
+		known, ok := normalizeStatus(defaultStatus)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Invalid --default-status %q (supported: %s)\n", defaultStatus, strings.Join(statuses, ", "))
+			return 1
+		}
+		defaultStatus = known
+	}
+
+	if opts.inputJSON != "" {
+		payload, err := readADRPayload(opts.inputJSON)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid --input-json:", err)
+			return 1
+		}
+		applyADRPayload(&opts, payload)
+	}
+	if opts.datetime && opts.date != "" {
+		fmt.Fprintln(os.Stderr, "Error: --datetime stamps the current time and can't be combined with a fixed date")
+		return 1
+	}
+
+	if templateEngine != "" && !slices.Contains(templateEngines, templateEngine) {
+		fmt.Fprintf(os.Stderr, "Invalid --template-engine %q (supported: %s)\n", templateEngine, strings.Join(templateEngines, ", "))
+		return 1
+	}
+	if !slices.Contains(planFormats, opts.planFormat) {
+		fmt.Fprintf(os.Stderr, "Invalid --format %q (supported: %s)\n", opts.planFormat, strings.Join(planFormats, ", "))
+		return 1
+	}
+	if opts.dryRun && opts.count > 1 {
+		fmt.Fprintln(os.Stderr, "Error: --dry-run is not supported with --count")
+		return 1
+	}
+
+	for _, pair := range opts.meta {
+		if _, _, err := parseMetaPair(pair); err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid --meta:", err)
+			return 1
+		}
+	}
+
+	if opts.title != "" {
+		if err := validateTitle(opts.title); err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid --title:", err)
+			return 1
+		}
+	}
+
+	if _, err := fsys.Stat(templatePath(templateName)); templateName != "" && !isRemoteURL(templateName) && err != nil {
+		fmt.Fprintf(os.Stderr, "Template %q not found: %v\n", templateName, err)
+		return 1
+	}
+
+	if !validIndexGrouping(indexGroupBy) {
+		fmt.Fprintf(os.Stderr, "Invalid --group-by value %q (supported: %s)\n", indexGroupBy, strings.Join(indexGroupings, ", "))
+		return 1
+	}
+
+	formatter, err := newIndexFormatter(indexFormat)
 	if err != nil {
-		fmt.Println("Error creating directory:", err)
-		return
+		fmt.Fprintln(os.Stderr, "Invalid --index-format:", err)
+		return 1
+	}
+
+	if _, err := statusIcons(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+
+	if _, _, err := indexDateRange(); err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid date filter:", err)
+		return 1
+	}
+
+	if indexFileName != "" && (strings.ContainsAny(indexFileName, `/\`) || isTemplateFile(indexFileName)) {
+		fmt.Fprintf(os.Stderr, "Invalid --index-file %q: must be a plain file name that isn't a template\n", indexFileName)
+		return 1
+	}
+
+	if opts.wrap < 0 {
+		fmt.Fprintln(os.Stderr, "Invalid --wrap value: must not be negative")
+		return 1
+	}
+
+	if opts.count < 1 {
+		fmt.Fprintln(os.Stderr, "Invalid --count value: must be at least 1")
+		return 1
+	}
+	if opts.count > 1 {
+		if opts.number != "" {
+			if err := validateNumber(opts.number); err != nil {
+				fmt.Fprintln(os.Stderr, "Invalid --number:", err)
+				return 1
+			}
+		}
+		if opts.status != "" {
+			known, ok := normalizeStatus(opts.status)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Invalid --status %q (supported: %s)\n", opts.status, strings.Join(statuses, ", "))
+				return 1
+			}
+			opts.status = known
+		}
+		if err := ensureDir(adrDir); err != nil {
+			fmt.Fprintln(os.Stderr, "Error creating directory:", err)
+			return 1
+		}
+
+		paths, err := createADRBlock(opts.count, opts)
+		for _, path := range paths {
+			fmt.Printf("✅ New ADR created successfully: %s\n", path)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error creating ADRs:", err)
+			if len(paths) == 0 {
+				return 1
+			}
+		}
+		if err := withRetry(updateIndex); err != nil {
+			fmt.Fprintln(os.Stderr, "Error updating index:", err)
+			return 1
+		}
+		if err != nil {
+			return 1
+		}
+		return 0
+	}
+
+	if opts.amends != "" {
+		if err := validateNumber(opts.amends); err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid --amends:", err)
+			return 1
+		}
+	}
+	if opts.from != "" {
+		if err := validateNumber(opts.from); err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid --from:", err)
+			return 1
+		}
+	}
+
+	number := opts.number
+	if number == "" && opts.selectADR {
+		number, err = promptForExistingADR()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Prompt failed %v\n", err)
+			return 1
+		}
+	} else if number == "" {
+		number, err = promptForNumber()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Prompt failed %v\n", err)
+			return 1
+		}
+	} else if err := validateNumber(number); err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid --number:", err)
+		return 1
+	}
+
+	status := opts.status
+	if status == "" {
+		status, err = promptForStatus()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Prompt failed %v\n", err)
+			return 1
+		}
+	} else if known, ok := normalizeStatus(status); ok {
+		status = known
+	} else {
+		fmt.Fprintf(os.Stderr, "Invalid --status %q (supported: %s)\n", status, strings.Join(statuses, ", "))
+		return 1
+	}
+
+	if !opts.dryRun {
+		if err := ensureDir(adrDir); err != nil {
+			fmt.Fprintln(os.Stderr, "Error creating directory:", err)
+			return 1
+		}
 	}
 
 	var oldFilename, filename, title string
 	isNewAdr := !adrExists(number)
+	if opts.forceNew && !isNewAdr {
+		files, err := readDirWithRetry(adrDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading directory:", err)
+			return 1
+		}
+		existing, _ := findADRFile(files, number)
+		if !opts.forceOverwrite {
+			fmt.Fprintf(os.Stderr, "Error: ADR %s already exists as %s, use --force-overwrite with --force-new to replace it\n", number, filepath.Join(adrDir, existing))
+			return 1
+		}
+		// The replaced file is removed once the new one is ready
+		oldFilename, isNewAdr = existing, true
+	}
+	if opts.amends != "" && (!isNewAdr || opts.amends == number) {
+		fmt.Fprintln(os.Stderr, "Error: --amends only applies to a new ADR, and an ADR cannot amend itself")
+		return 1
+	}
+	if opts.from != "" && (!isNewAdr || opts.from == number) {
+		fmt.Fprintln(os.Stderr, "Error: --from only applies to a new ADR, and must name a different one")
+		return 1
+	}
 
 	if isNewAdr {
-		title, err = promptForTitle("")
-		if err != nil {
-			fmt.Printf("Prompt failed %v\n", err)
-			return
+		title = opts.title
+		if title == "" {
+			title, err = promptForTitle("")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Prompt failed %v\n", err)
+				return 1
+			}
+
+			// Interactive creation also asks for the template's own placeholders
+			if template, err := expandIncludes(loadTemplateOrDefault()); err == nil {
+				opts.templateVars, err = promptForPlaceholders(template, opts.templateVars)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Prompt failed %v\n", err)
+					return 1
+				}
+			}
+		}
+		filename = adrFilename(number, title)
+
+		// The file may have been created outside of adrgen
+		if _, err := fsys.Stat(filepath.Join(adrDir, filename)); err == nil && !opts.forceOverwrite {
+			fmt.Fprintf(os.Stderr, "Error: %s already exists, use --force-overwrite to replace it\n", filepath.Join(adrDir, filename))
+			return 1
 		}
-		kebabTitle := toKebabCase(title)
-		filename = fmt.Sprintf("adr-%s-%s.md", number, kebabTitle)
 	} else {
 		// For updates, find the existing file
-		files, err := os.ReadDir(adrDir)
+		files, err := readDirWithRetry(adrDir)
 		if err != nil {
-			fmt.Println("Error reading directory:", err)
-			return
-		}
-		prefix := fmt.Sprintf("adr-%s-", number)
-		for _, file := range files {
-			if strings.HasPrefix(file.Name(), prefix) {
-				oldFilename = file.Name()
-				break
-			}
+			fmt.Fprintln(os.Stderr, "Error reading directory:", err)
+			return 1
 		}
+		oldFilename, _ = findADRFile(files, number)
 
 		// Read existing content to get current title
-		existingContent, err := os.ReadFile(filepath.Join(adrDir, oldFilename))
+		existingContent, err := readFileWithRetry(filepath.Join(adrDir, oldFilename))
 		if err != nil {
-			fmt.Println("Error reading existing ADR:", err)
-			return
+			fmt.Fprintln(os.Stderr, "Error reading existing ADR:", err)
+			return 1
 		}
 
 		currentTitle := getCurrentTitle(string(existingContent))
-		title, err = promptForTitle(currentTitle)
-		if err != nil {
-			fmt.Printf("Prompt failed %v\n", err)
-			return
+		switch {
+		case opts.title != "":
+			title = opts.title
+		case opts.number != "" && currentTitle != "":
+			// Flag-driven updates keep the current title unless --title is given
+			title = currentTitle
+		default:
+			title, err = promptForTitle(currentTitle)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Prompt failed %v\n", err)
+				return 1
+			}
 		}
 
-		// Only update filename if title changed
-		if title != currentTitle {
-			kebabTitle := toKebabCase(title)
-			filename = fmt.Sprintf("adr-%s-%s.md", number, kebabTitle)
+		// Only update filename if title changed, and not with --keep-filename
+		if title != currentTitle && !opts.keepFilename {
+			filename = renderFilename(number, title, oldFilename)
 		} else {
 			filename = oldFilename
 		}
 	}
 
+	if filename != oldFilename {
+		files, err := readDirWithRetry(adrDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading directory:", err)
+			return 1
+		}
+		if other, ok := caseCollision(files, filename, oldFilename); ok {
+			fmt.Fprintf(os.Stderr, "Error: %s differs from the existing %s only by case, so they would be the same file on macOS and Windows\n", filename, filepath.Join(adrDir, other))
+			return 1
+		}
+		if oldFilename != "" && foldsCase(adrDir, files, filename, oldFilename) {
+			// Removing the old name would delete the file the new name opens,
+			// so a case-only rename rewrites the file in place
+			filename = oldFilename
+		}
+	}
+
 	fullPath := filepath.Join(adrDir, filename)
-	date := time.Now().Format("2006-01-02")
+	now := time.Now()
+	date := now.Format("2006-01-02")
+	if opts.date != "" {
+		date = opts.date
+	}
+	if opts.datetime {
+		opts.timestamp = now.Format(time.RFC3339)
+	}
 
-	var content string
+	var content, amendedPath, amendedContent string
 	if isNewAdr {
-		template := loadTemplateOrDefault()
-		content = renderTemplate(template, number, status, title, date)
+		if opts.from != "" {
+			content, err = cloneADR(opts.from, number, status, title, date, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error copying ADR %s: %v\n", opts.from, err)
+				return 1
+			}
+		} else {
+			content, err = newADRContent(number, status, title, date, opts)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error rendering template:", err)
+				return 1
+			}
+		}
+		if opts.note != "" {
+			content = appendDecisionLog(content, date, "", status, opts.note)
+		}
+		if opts.amends != "" {
+			files, err := readDirWithRetry(adrDir)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error reading directory:", err)
+				return 1
+			}
+			parentFile, ok := findADRFile(files, opts.amends)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: ADR %s to amend not found in %s\n", opts.amends, adrDir)
+				return 1
+			}
+			parent, err := readFileWithRetry(filepath.Join(adrDir, parentFile))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error reading amended ADR:", err)
+				return 1
+			}
+			content = writeRelation(content, Relation{Type: "Amends", TargetNumber: opts.amends}, parentFile)
+			// The amended ADR keeps its status, it only gains the back reference
+			if relation := (Relation{Type: "Amended by", TargetNumber: number}); !hasRelation(string(parent), relation) {
+				amendedPath, amendedContent = filepath.Join(adrDir, parentFile), writeRelation(string(parent), relation, filename)
+			}
+		}
 	} else {
 		// Read existing file
-		existingContent, err := os.ReadFile(filepath.Join(adrDir, oldFilename))
+		existingContent, err := readFileWithRetry(filepath.Join(adrDir, oldFilename))
 		if err != nil {
-			fmt.Println("Error reading existing ADR:", err)
-			return
+			fmt.Fprintln(os.Stderr, "Error reading existing ADR:", err)
+			return 1
 		}
-		content = updateStatus(string(existingContent), status)
-		content = updateTitle(content, title)
-
-		// If filename changed, remove old file
-		if filename != oldFilename {
-			err = os.Remove(filepath.Join(adrDir, oldFilename))
-			if err != nil {
-				fmt.Printf("Warning: Could not remove old file: %v\n", err)
+		content = string(existingContent)
+
+		// Same status and title: nothing to do, not even a rewrite
+		if strings.EqualFold(strings.TrimSpace(getCurrentStatus(content)), status) && filename == oldFilename &&
+			title == getCurrentTitle(content) && opts.note == "" && !opts.canonicalize && len(opts.meta) == 0 {
+			if opts.dryRun && opts.planFormat == "json" {
+				// An empty plan keeps the output parseable
+				if err := printPlan(nil, opts.planFormat); err != nil {
+					fmt.Fprintln(os.Stderr, "Error:", err)
+					return 1
+				}
+				return 0
 			}
+			fmt.Printf("No changes: status unchanged, %s is already %s\n", fullPath, getCurrentStatus(content))
+			return 0
+		}
+
+		if opts.canonicalize {
+			content = canonicalizeFields(content, number)
+		}
+		previousStatus := getCurrentStatus(content)
+		content = updateStatus(content, status)
+		content = updateTitle(content, number, title)
+		if opts.note != "" {
+			content = appendDecisionLog(content, date, previousStatus, status, opts.note)
 		}
 	}
 
-	err = writeFile(fullPath, content)
-	if err != nil {
-		fmt.Println("Error writing ADR:", err)
-		return
+	for _, pair := range opts.meta {
+		key, value, _ := parseMetaPair(pair)
+		content = setFooterField(content, key, value)
 	}
 
-	err = updateIndex()
-	if err != nil {
-		fmt.Println("Error updating index:", err)
-		return
+	if err := runPreWriteHook(fullPath, content); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
 	}
 
+	// Everything the run changes is planned first, so --dry-run can report it
+	var ops []fileOp
+	if oldFilename != "" && filename != oldFilename {
+		oldPath := filepath.Join(adrDir, oldFilename)
+		ops = append(ops, warnOnFailure(fileOp{Op: "remove", Path: oldPath, apply: func() error {
+			if err := withRetry(func() error { return fsys.Remove(oldPath) }); err != nil {
+				return err
+			}
+			debugf("removed %s", oldPath)
+			return nil
+		}}, "Could not remove old file"))
+
+		// Companion files follow a renamed ADR and go with a replaced one
+		target := fullPath
+		if isNewAdr {
+			target = ""
+		}
+		moves, err := planCompanionMoves(oldPath, target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not update companion files: %v\n", err)
+		}
+		for _, op := range moves {
+			ops = append(ops, warnOnFailure(op, "Could not update companion files"))
+		}
+	}
+
+	changed := true
+	if existing, err := fsys.ReadFile(fullPath); err == nil && string(existing) == content {
+		changed = false
+	} else {
+		op := "update"
+		if err != nil {
+			op = "create"
+		}
+		ops = append(ops, fileOp{Op: op, Path: fullPath, apply: func() error {
+			if err := withRetry(func() error { return writeFile(fullPath, content) }); err != nil {
+				return fmt.Errorf("writing ADR: %w", err)
+			}
+			return nil
+		}})
+	}
+	if amendedPath != "" {
+		ops = append(ops, fileOp{Op: "update", Path: amendedPath, apply: func() error {
+			if err := withRetry(func() error { return writeFile(amendedPath, amendedContent) }); err != nil {
+				return fmt.Errorf("updating amended ADR: %w", err)
+			}
+			fmt.Printf("✅ Added \"Amended by: ADR %s\" to %s\n", number, amendedPath)
+			return nil
+		}})
+	}
 	if isNewAdr {
+		vars := map[string]string{"number": number, "status": status, "title": title, "date": date, "category": category}
+		for key, value := range opts.templateVars {
+			vars[key] = value
+		}
+		created, err := planCompanions(fullPath, vars)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error creating companion files:", err)
+			return 1
+		}
+		ops = append(ops, created...)
+	}
+	ops = append(ops, fileOp{Op: "write-index", Path: indexPath(formatter), apply: func() error {
+		if err := withRetry(updateIndex); err != nil {
+			return fmt.Errorf("updating index: %w", err)
+		}
+		return nil
+	}})
+
+	if opts.dryRun {
+		if err := printPlan(ops, opts.planFormat); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+	if err := applyPlan(ops); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if !changed {
+		fmt.Printf("No changes: %s is already up to date\n", fullPath)
+	} else if isNewAdr {
 		fmt.Printf("✅ New ADR created successfully: %s\n", fullPath)
 	} else {
 		if filename != oldFilename {
@@ -445,4 +1625,12 @@ func main() {
 			fmt.Printf("✅ ADR updated successfully: %s\n", fullPath)
 		}
 	}
+
+	if opts.openIndex {
+		err = openFile(indexPath(formatter))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not open index: %v\n", err)
+		}
+	}
+	return 0
 }
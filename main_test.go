@@ -1,10 +1,16 @@
 package main
 
 import (
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestToKebabCase(t *testing.T) {
@@ -28,6 +34,97 @@ func TestToKebabCase(t *testing.T) {
 	}
 }
 
+func TestToKebabCaseSmartSlug(t *testing.T) {
+	original := smartSlug
+	smartSlug = true
+	defer func() { smartSlug = original }()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"databaseChoice", "database-choice"},
+		{"DatabaseChoice", "database-choice"},
+		{"HTTPServer", "http-server"},
+		{"UseHTTPForAPIs", "use-http-for-apis"},
+		{"Route53Failover", "route53-failover"},
+		{"DATABASE_CHOICE", "database-choice"},
+		{"Use Kafka", "use-kafka"},
+	}
+
+	for _, test := range tests {
+		result := toKebabCase(test.input)
+		if result != test.expected {
+			t.Errorf("toKebabCase(%q) = %q, want %q", test.input, result, test.expected)
+		}
+	}
+
+	smartSlug = false
+	if result := toKebabCase("DatabaseChoice"); result != "databasechoice" {
+		t.Errorf("toKebabCase() without --smart-slug = %q, want %q", result, "databasechoice")
+	}
+}
+
+func TestCustomSeparator(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalSeparator := adrDir, separator
+	adrDir, separator = tempDir, "_"
+	defer func() { adrDir, separator = originalAdrDir, originalSeparator }()
+
+	if result := toKebabCase("Use Redis_Cache-Layer"); result != "use_redis_cache_layer" {
+		t.Errorf("toKebabCase() = %q, want %q", result, "use_redis_cache_layer")
+	}
+	if result := adrFilename("004", "Use Redis"); result != "adr_004_use_redis.md" {
+		t.Errorf("adrFilename() = %q, want %q", result, "adr_004_use_redis.md")
+	}
+	if result := extractTitleFromFilename("004_use_redis.md"); result != "Use Redis" {
+		t.Errorf("extractTitleFromFilename() = %q, want %q", result, "Use Redis")
+	}
+
+	for _, file := range []string{"adr_001_first.md", "adr_004_use_redis.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if !adrExists("004") {
+		t.Error("adrExists() returned false for existing ADR with custom separator")
+	}
+	if result := getNextADRNumber(); result != "005" {
+		t.Errorf("getNextADRNumber() = %q, want %q", result, "005")
+	}
+}
+
+func TestLooksLikeProjectRoot(t *testing.T) {
+	adrDir := t.TempDir()
+	for _, file := range []string{"adr-001-first.md", "adr-002-second.md", indexFile, templateFile} {
+		if err := writeFile(filepath.Join(adrDir, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+	if isRoot, reason := looksLikeProjectRoot(adrDir); isRoot {
+		t.Errorf("looksLikeProjectRoot() = true (%s) for an ADR directory", reason)
+	}
+
+	goRoot := t.TempDir()
+	if err := writeFile(filepath.Join(goRoot, "go.mod"), "module example.com/x\n"); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+	if isRoot, _ := looksLikeProjectRoot(goRoot); !isRoot {
+		t.Error("looksLikeProjectRoot() = false for a directory containing go.mod")
+	}
+
+	docsRoot := t.TempDir()
+	for _, file := range []string{"CONTRIBUTING.md", "CHANGELOG.md", "SECURITY.md", "adr-001-first.md"} {
+		if err := writeFile(filepath.Join(docsRoot, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+	if isRoot, _ := looksLikeProjectRoot(docsRoot); !isRoot {
+		t.Error("looksLikeProjectRoot() = false for a directory with many non-ADR Markdown files")
+	}
+}
+
 func TestEnsureDir(t *testing.T) {
 	tempDir := t.TempDir()
 	testPath := filepath.Join(tempDir, "test", "nested", "dir")
@@ -67,6 +164,98 @@ func TestWriteFile(t *testing.T) {
 	}
 }
 
+func TestWriteFileTruncatesExisting(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.txt")
+
+	if err := writeFile(testFile, "a much longer original content"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := writeFile(testFile, "short"); err != nil {
+		t.Fatalf("writeFile(%q) failed: %v", testFile, err)
+	}
+
+	content, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	if string(content) != "short" {
+		t.Errorf("File content = %q, want %q", string(content), "short")
+	}
+}
+
+func TestWriteFileReplacesAtomically(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "adr-001-use-go.md")
+
+	if err := os.WriteFile(testFile, []byte("original"), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := writeFile(testFile, "replaced"); err != nil {
+		t.Fatalf("writeFile(%q) failed: %v", testFile, err)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", tempDir, err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("writeFile() left temporary files behind: %v", entries)
+	}
+	info, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatalf("Failed to stat test file: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("File mode = %v, want the original 0600", info.Mode().Perm())
+	}
+}
+
+func TestSyncDirError(t *testing.T) {
+	if err := syncDir(filepath.Join(t.TempDir(), "missing")); err == nil && runtime.GOOS != "windows" {
+		t.Error("Expected error when syncing a missing directory")
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	originalAttempts, originalDelay := retryAttempts, retryDelay
+	retryAttempts, retryDelay = 3, time.Millisecond
+	defer func() { retryAttempts, retryDelay = originalAttempts, originalDelay }()
+
+	// Transient errors are retried until the operation succeeds
+	calls := 0
+	err := withRetry(func() error {
+		calls++
+		if calls < 3 {
+			return &os.PathError{Op: "open", Path: "adr.md", Err: syscall.EAGAIN}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("withRetry() = %v after %d calls, want nil after 3", err, calls)
+	}
+
+	// Other errors are returned immediately
+	calls = 0
+	err = withRetry(func() error {
+		calls++
+		return os.ErrNotExist
+	})
+	if !errors.Is(err, os.ErrNotExist) || calls != 1 {
+		t.Errorf("withRetry() = %v after %d calls, want ErrNotExist after 1", err, calls)
+	}
+
+	// The last transient error is surfaced once attempts run out
+	calls = 0
+	err = withRetry(func() error {
+		calls++
+		return syscall.EBUSY
+	})
+	if !errors.Is(err, syscall.EBUSY) || calls != 3 {
+		t.Errorf("withRetry() = %v after %d calls, want EBUSY after 3", err, calls)
+	}
+}
+
 func TestExtractTitleFromFilename(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -76,6 +265,32 @@ func TestExtractTitleFromFilename(t *testing.T) {
 		{"002-adr-template.md", "ADR Template"},
 		{"simple.md", "simple.md"},
 		{"003-multiple-word-title.md", "Multiple Word Title"},
+		{"adr-004-database-choice.md", "Database Choice"},
+		{"adr-005-adr-tooling.md", "ADR Tooling"},
+		{"adr-006-badr-adrenaline.md", "Badr Adrenaline"},
+		{"adr-notes.md", "adr-notes.md"},
+	}
+
+	for _, test := range tests {
+		result := extractTitleFromFilename(test.input)
+		if result != test.expected {
+			t.Errorf("extractTitleFromFilename(%q) = %q, want %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestExtractTitleFromFilenameLegacy(t *testing.T) {
+	originalTemplate := filenameTemplate
+	filenameTemplate = "{{year}}-{{number}}-{{slug}}.md"
+	defer func() { filenameTemplate = originalTemplate }()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"2024-007-event-sourcing.md", "Event Sourcing"},
+		{"007-event-sourcing.md", "Event Sourcing"},
+		{"adr-007-event-sourcing.md", "Event Sourcing"},
 	}
 
 	for _, test := range tests {
@@ -167,6 +382,75 @@ func TestRenderTemplate(t *testing.T) {
 	}
 }
 
+func TestTemplateVarFlag(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	template := "# ADR {{number}}: {{title}}\n\nTicket: {{ticket}}\nOwner: {{owner}}\nTeam: {{team}}\n"
+	if err := writeFile(filepath.Join(tempDir, templateFile), template); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	var opts createOptions
+	for _, pair := range []string{"ticket=ARCH-42", "owner=alice", "owner=bob"} {
+		if err := opts.templateVars.Set(pair); err != nil {
+			t.Fatalf("Set(%q) failed: %v", pair, err)
+		}
+	}
+	for _, pair := range []string{"ticket", "=x", "bad key=x", "title=Other"} {
+		if err := opts.templateVars.Set(pair); err == nil {
+			t.Errorf("Set(%q) should have failed", pair)
+		}
+	}
+
+	var content string
+	var err error
+	_, stderr := captureOutput(t, func() {
+		content, err = newADRContent("001", "Accepted", "Use Go", "2024-01-01", opts)
+	})
+	if err != nil {
+		t.Fatalf("newADRContent() failed: %v", err)
+	}
+	expected := "# ADR 001: Use Go\n\nTicket: ARCH-42\nOwner: bob\nTeam: {{team}}\n"
+	if content != expected {
+		t.Errorf("newADRContent() = %q, want %q", content, expected)
+	}
+	if !strings.Contains(stderr, "{{team}}") {
+		t.Errorf("stderr = %q, want a warning about {{team}}", stderr)
+	}
+}
+
+func TestRenderTemplateVars(t *testing.T) {
+	template := "ADR {{number}} by {{author}} at {{commit}} {{unknown}}"
+	vars := map[string]string{
+		"number": "001",
+		"author": "Ada <ada@example.com>",
+		"commit": "abc1234",
+	}
+
+	expected := "ADR 001 by Ada <ada@example.com> at abc1234 {{unknown}}"
+	if result := renderTemplateVars(template, vars); result != expected {
+		t.Errorf("renderTemplateVars() = %q, want %q", result, expected)
+	}
+}
+
+func TestRenderTemplateWith(t *testing.T) {
+	template := "{{title}} ({{ticket}}) {{unknown}}"
+	resolve := func(key string) (string, bool) {
+		if key == "ticket" {
+			return "https://tracker.example.com/ADR-1", true
+		}
+		return "", false
+	}
+
+	expected := "Use Kafka (https://tracker.example.com/ADR-1) {{unknown}}"
+	if result := renderTemplateWith(template, map[string]string{"title": "Use Kafka"}, resolve); result != expected {
+		t.Errorf("renderTemplateWith() = %q, want %q", result, expected)
+	}
+}
+
 func TestAdrExists(t *testing.T) {
 	// Create temporary ADR directory
 	tempDir := t.TempDir()
@@ -191,6 +475,286 @@ func TestAdrExists(t *testing.T) {
 	}
 }
 
+func TestGetNextADRNumberOptions(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalWidth, originalFillGaps := adrDir, numberWidth, fillGaps
+	adrDir = tempDir
+	defer func() { adrDir, numberWidth, fillGaps = originalAdrDir, originalWidth, originalFillGaps }()
+
+	for _, file := range []string{"adr-001-first.md", "adr-002-second.md", "adr-005-fifth.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	tests := []struct {
+		width    int
+		fillGaps bool
+		expected string
+	}{
+		{3, false, "006"},
+		{3, true, "003"},
+		{4, false, "0006"},
+		{1, true, "3"},
+	}
+
+	for _, test := range tests {
+		numberWidth, fillGaps = test.width, test.fillGaps
+		if result := getNextADRNumber(); result != test.expected {
+			t.Errorf("getNextADRNumber() with width %d, fill gaps %v = %q, want %q", test.width, test.fillGaps, result, test.expected)
+		}
+	}
+}
+
+func TestGetNextADRNumberBase(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalBase, originalFillGaps := adrDir, baseNumber, fillGaps
+	adrDir, baseNumber = tempDir, 100
+	defer func() { adrDir, baseNumber, fillGaps = originalAdrDir, originalBase, originalFillGaps }()
+
+	if result := getNextADRNumber(); result != "100" {
+		t.Errorf("getNextADRNumber() in an empty directory = %q, want 100", result)
+	}
+
+	for _, file := range []string{"adr-001-first.md", "adr-002-second.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+	fillGaps = true
+	if result := getNextADRNumber(); result != "100" {
+		t.Errorf("getNextADRNumber() with --fill-gaps below the base = %q, want 100", result)
+	}
+
+	fillGaps = false
+	if err := writeFile(filepath.Join(tempDir, "adr-120-imported.md"), "test content"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if result := getNextADRNumber(); result != "121" {
+		t.Errorf("getNextADRNumber() above the base = %q, want 121", result)
+	}
+}
+
+func TestCreateADRBlock(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := writeFile(filepath.Join(tempDir, "adr-011-existing.md"), "# ADR 011: Existing\n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	paths, err := createADRBlock(3, createOptions{title: "Storage"})
+	if err != nil {
+		t.Fatalf("createADRBlock() failed: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(tempDir, "adr-012-storage-1.md"),
+		filepath.Join(tempDir, "adr-013-storage-2.md"),
+		filepath.Join(tempDir, "adr-014-storage-3.md"),
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("createADRBlock() = %v, want %v", paths, expected)
+	}
+	content, err := os.ReadFile(expected[0])
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", expected[0], err)
+	}
+	if getCurrentStatus(string(content)) != "Proposed" {
+		t.Errorf("Placeholder status = %q, want Proposed", getCurrentStatus(string(content)))
+	}
+
+	// A block overlapping existing ADRs writes nothing
+	if _, err := createADRBlock(2, createOptions{number: "014"}); err == nil {
+		t.Error("Expected an error for a block overlapping an existing ADR")
+	}
+	if adrExists("015") {
+		t.Error("createADRBlock() wrote ADR 015 despite the conflict")
+	}
+}
+
+// captureOutput runs fn and returns what it wrote to stdout and stderr.
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	originalStdout, originalStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutW, stderrW
+	defer func() { os.Stdout, os.Stderr = originalStdout, originalStderr }()
+
+	fn()
+	stdoutW.Close()
+	stderrW.Close()
+
+	stdout, _ := io.ReadAll(stdoutR)
+	stderr, _ := io.ReadAll(stderrR)
+	return string(stdout), string(stderr)
+}
+
+func TestRunCreateOutputStreams(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	var code int
+	stdout, stderr := captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--number", "001", "--status", "Bogus", "--title", "Use Go"})
+	})
+	if code != 1 {
+		t.Errorf("runCreate() = %d for an invalid status, want 1", code)
+	}
+	if stdout != "" {
+		t.Errorf("Failing run wrote to stdout: %q", stdout)
+	}
+	if !strings.Contains(stderr, "Invalid --status") {
+		t.Errorf("stderr = %q, want the invalid status error", stderr)
+	}
+
+	stdout, stderr = captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--number", "001", "--status", "Accepted", "--title", "Use Go"})
+	})
+	if code != 0 {
+		t.Errorf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+	if stderr != "" {
+		t.Errorf("Successful run wrote to stderr: %q", stderr)
+	}
+	if !strings.Contains(stdout, filepath.Join(tempDir, "adr-001-use-go.md")) {
+		t.Errorf("stdout = %q, want the created file path", stdout)
+	}
+}
+
+func TestRunCreateKeepFilename(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	path := filepath.Join(tempDir, "adr-001-use-go.md")
+	if err := writeFile(path, "# ADR 001: Use Go\n\n**Status**: Accepted  \n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--number", "001", "--status", "Accepted", "--title", "Use Go Everywhere", "--keep-filename"})
+	})
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Retitled ADR was renamed: %v", err)
+	}
+	if getCurrentTitle(string(content)) != "Use Go Everywhere" {
+		t.Errorf("Title = %q, want %q", getCurrentTitle(string(content)), "Use Go Everywhere")
+	}
+}
+
+func TestRunCreateRefusesOverwrite(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalIgnore := adrDir, ignorePatterns
+	defer func() { adrDir, ignorePatterns = originalAdrDir, originalIgnore }()
+
+	// Ignored, so ADR 002 looks new while its filename is taken
+	path := filepath.Join(tempDir, "adr-002-use-go.md")
+	original := "Notes written by hand, not an ADR\n"
+	if err := writeFile(path, original); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	args := []string{"--dir", tempDir, "--number", "002", "--status", "Proposed", "--title", "Use Go", "--ignore", "adr-002-*.md"}
+	var code int
+	_, stderr := captureOutput(t, func() { code = runCreate(args) })
+	if code != 1 || !strings.Contains(stderr, "already exists, use --force-overwrite") {
+		t.Errorf("runCreate() = %d (stderr: %q), want a refusal pointing at --force-overwrite", code, stderr)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	if string(content) != original {
+		t.Errorf("File content = %q, want it unchanged", content)
+	}
+}
+
+func TestRunCreateForceNew(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	oldPath := filepath.Join(tempDir, "adr-003-old-idea.md")
+	if err := writeFile(oldPath, "# ADR 003: Old Idea\n\n**Status**: Rejected  \n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	args := []string{"--dir", tempDir, "--number", "003", "--status", "Proposed", "--title", "Fresh Start", "--force-new"}
+	var code int
+	_, stderr := captureOutput(t, func() { code = runCreate(args) })
+	if code != 1 || !strings.Contains(stderr, "--force-overwrite") {
+		t.Errorf("runCreate() = %d (stderr: %q), want a refusal pointing at --force-overwrite", code, stderr)
+	}
+
+	_, stderr = captureOutput(t, func() { code = runCreate(append(args, "--force-overwrite")) })
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Error("Replaced ADR was left behind")
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "adr-003-fresh-start.md"))
+	if err != nil {
+		t.Fatalf("New ADR was not created: %v", err)
+	}
+	if strings.Contains(string(content), "Previous Status") || getCurrentStatus(string(content)) != "Proposed" {
+		t.Errorf("New ADR = %q, want fresh template content", content)
+	}
+}
+
+func TestRunCreateUnchanged(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	args := []string{"--dir", tempDir, "--number", "001", "--status", "Accepted", "--title", "Use Go"}
+	var code int
+	captureOutput(t, func() { code = runCreate(args) })
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0", code)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	for _, file := range []string{"adr-001-use-go.md", indexFile} {
+		if err := os.Chtimes(filepath.Join(tempDir, file), past, past); err != nil {
+			t.Fatalf("Failed to set modification time: %v", err)
+		}
+	}
+
+	stdout, _ := captureOutput(t, func() { runCreate(args) })
+	if !strings.Contains(stdout, "No changes") {
+		t.Errorf("stdout = %q, want a no changes message", stdout)
+	}
+	for _, file := range []string{"adr-001-use-go.md", indexFile} {
+		info, err := os.Stat(filepath.Join(tempDir, file))
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", file, err)
+		}
+		if !info.ModTime().Equal(past) {
+			t.Errorf("%s was rewritten although nothing changed", file)
+		}
+	}
+}
+
 func TestUpdateIndexError(t *testing.T) {
 	// Create temporary directory
 	tempDir := t.TempDir()
@@ -233,6 +797,22 @@ func TestWriteFileError(t *testing.T) {
 	}
 }
 
+func TestOpenCommandHeadless(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("headless detection only applies to Unix desktops")
+	}
+
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	if cmd := openCommand("README.md"); cmd != nil {
+		t.Errorf("openCommand() = %v, want nil without a display", cmd.Args)
+	}
+	if err := openFile("README.md"); err != nil {
+		t.Errorf("openFile() failed without a display: %v", err)
+	}
+}
+
 // func TestMainWithDirectoryError(t *testing.T) {
 // 	// Save original args and restore them after the test
 // 	oldArgs := os.Args
@@ -557,3 +1137,204 @@ func TestWriteFileError(t *testing.T) {
 // 		t.Error("Expected error when updating read-only index file")
 // 	}
 // }
+
+func TestValidateTitleMaxLength(t *testing.T) {
+	originalMax := maxTitleLength
+	defer func() { maxTitleLength = originalMax }()
+
+	title := strings.Repeat("é", 20)
+	maxTitleLength = 0
+	if err := validateTitle(title); err != nil {
+		t.Errorf("validateTitle() without a limit failed: %v", err)
+	}
+	maxTitleLength = 20
+	if err := validateTitle(title); err != nil {
+		t.Errorf("validateTitle() at the limit failed: %v", err)
+	}
+	maxTitleLength = 19
+	err := validateTitle(title)
+	if err == nil || !strings.Contains(err.Error(), "20 characters long, the maximum is 19") {
+		t.Errorf("validateTitle() = %v, want the length and the maximum", err)
+	}
+}
+
+func TestRunCreateStatusUnchanged(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	// Legacy fields whose trailing whitespace a rewrite would normalize
+	path := filepath.Join(tempDir, "adr-001-use-go.md")
+	original := "Title: Use Go  \nStatus: accepted  \n\n## Context\n"
+	if err := writeFile(path, original); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var code int
+	stdout, stderr := captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--number", "001", "--status", "Accepted"})
+	})
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+	if !strings.Contains(stdout, "status unchanged") {
+		t.Errorf("stdout = %q, want a status unchanged message", stdout)
+	}
+	if content, _ := os.ReadFile(path); string(content) != original {
+		t.Errorf("ADR was rewritten: %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, indexFile)); !os.IsNotExist(err) {
+		t.Error("A no-op update wrote the index")
+	}
+}
+
+func TestRunCreateDefaultStatus(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalDefault := adrDir, defaultStatus
+	defer func() { adrDir, defaultStatus = originalAdrDir, originalDefault }()
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--default-status", "Parked", "--count", "2"})
+	})
+	if code != 1 || !strings.Contains(stderr, "Invalid --default-status") {
+		t.Errorf("runCreate() = %d (stderr: %q), want an invalid default status error", code, stderr)
+	}
+
+	_, stderr = captureOutput(t, func() {
+		code = runCreate([]string{"--dir", tempDir, "--default-status", "deprecated", "--count", "2"})
+	})
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0 (stderr: %q)", code, stderr)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "adr-001-tbd.md"))
+	if err != nil {
+		t.Fatalf("Placeholder ADR missing: %v", err)
+	}
+	if status := getCurrentStatus(string(content)); status != "Deprecated" {
+		t.Errorf("Placeholder status = %q, want the default status", status)
+	}
+}
+
+func TestGetNextADRNumberIgnoresBackups(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalIgnore := adrDir, ignorePatterns
+	adrDir = tempDir
+	defer func() { adrDir, ignorePatterns = originalAdrDir, originalIgnore }()
+
+	for _, file := range []string{"adr-001-first.md", ".#adr-009-lock.md", "adr-003-database-copy.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "# ADR 001: First\n"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	// A title ending in "Copy" is an ADR, not an editor's copy
+	if result := getNextADRNumber(); result != "004" {
+		t.Errorf("getNextADRNumber() = %q, want 004 with backups ignored", result)
+	}
+	if err := updateIndex(); err != nil {
+		t.Fatalf("updateIndex() failed: %v", err)
+	}
+	index, err := os.ReadFile(filepath.Join(tempDir, indexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), "(adr-003-database-copy.md)") || strings.Contains(string(index), "lock") {
+		t.Errorf("Index = %q, want ADR 003 listed and the lock file left out", index)
+	}
+
+	ignorePatterns = "*-copy.md"
+	if result := getNextADRNumber(); result != "002" {
+		t.Errorf("getNextADRNumber() with --ignore %q = %q, want 002", ignorePatterns, result)
+	}
+}
+
+func TestRunCreateCaseCollision(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := writeFile(filepath.Join(tempDir, "ADR-005-Use-Go.md"), "# ADR 005: Use Go\n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	args := []string{"--dir", tempDir, "--number", "005", "--status", "Proposed", "--title", "Use Go"}
+	var code int
+	_, stderr := captureOutput(t, func() { code = runCreate(args) })
+	if code != 1 || !strings.Contains(stderr, "only by case") {
+		t.Errorf("runCreate() = %d (stderr: %q), want a case collision error", code, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "adr-005-use-go.md")); !os.IsNotExist(err) {
+		t.Error("runCreate() wrote a file colliding by case")
+	}
+}
+
+func TestRunCreateDatetime(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	args := []string{"--dir", tempDir, "--number", "001", "--status", "Accepted", "--title", "Audit Trail", "--datetime"}
+	var code int
+	captureOutput(t, func() { code = runCreate(args) })
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0", code)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "adr-001-audit-trail.md"))
+	if err != nil {
+		t.Fatalf("ADR was not created: %v", err)
+	}
+	timestamp := extractTimestamp(string(content))
+	stamped, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		t.Fatalf("Timestamp %q is not RFC3339 in %q", timestamp, content)
+	}
+	if extractDate(string(content)) != stamped.Format("2006-01-02") {
+		t.Errorf("Date %q doesn't match Timestamp %q", extractDate(string(content)), timestamp)
+	}
+}
+
+func TestRunCreateAmends(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	parentPath := filepath.Join(tempDir, "adr-004-use-postgres.md")
+	if err := writeFile(parentPath, "# ADR 004: Use Postgres\n\n**Status**: Accepted  \n**Date**: 2024-01-01\n"); err != nil {
+		t.Fatalf("Failed to create ADR: %v", err)
+	}
+
+	args := []string{"--dir", tempDir, "--number", "005", "--status", "Accepted", "--title", "Use Postgres Replicas", "--amends", "004"}
+	var code int
+	captureOutput(t, func() { code = runCreate(args) })
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0", code)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "adr-005-use-postgres-replicas.md"))
+	if err != nil {
+		t.Fatalf("ADR was not created: %v", err)
+	}
+	if !hasRelation(string(content), Relation{Type: "Amends", TargetNumber: "004"}) {
+		t.Errorf("New ADR has no Amends relation to 004:\n%s", content)
+	}
+	parent, err := os.ReadFile(parentPath)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", parentPath, err)
+	}
+	if !hasRelation(string(parent), Relation{Type: "Amended by", TargetNumber: "005"}) {
+		t.Errorf("ADR 004 has no Amended by relation to 005:\n%s", parent)
+	}
+	if status := getCurrentStatus(string(parent)); status != "Accepted" {
+		t.Errorf("ADR 004 status = %q, want it left Accepted", status)
+	}
+
+	args = []string{"--dir", tempDir, "--number", "006", "--status", "Accepted", "--title", "Orphan", "--amends", "009"}
+	captureOutput(t, func() { code = runCreate(args) })
+	if code == 0 {
+		t.Error("runCreate() should fail when the amended ADR doesn't exist")
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "adr-006-orphan.md")); err == nil {
+		t.Error("runCreate() wrote the ADR despite the missing amended ADR")
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	markdownOrderedPattern = regexp.MustCompile(`^\d+[.)]\s+`)
+	markdownImagePattern   = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	markdownLinkHTML       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBoldPattern    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownItalicPattern  = regexp.MustCompile(`(^|[^*\w])[*_]([^*_\s][^*_]*)[*_]($|[^*\w])`)
+)
+
+// markdownToHTML renders the Markdown ADRs use (headings, paragraphs, lists,
+// block quotes, rules, code blocks, links and emphasis) as an HTML fragment.
+// Anything else, such as tables, comes out as plain paragraphs.
+func markdownToHTML(src string) string {
+	var b strings.Builder
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var paragraph, quote []string
+	list := ""
+	flush := func() {
+		if len(paragraph) > 0 {
+			fmt.Fprintf(&b, "<p>%s</p>\n", inlineHTML(strings.Join(paragraph, " ")))
+			paragraph = nil
+		}
+		if len(quote) > 0 {
+			fmt.Fprintf(&b, "<blockquote><p>%s</p></blockquote>\n", inlineHTML(strings.Join(quote, " ")))
+			quote = nil
+		}
+		if list != "" {
+			fmt.Fprintf(&b, "</%s>\n", list)
+			list = ""
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			b.WriteString("<pre><code>")
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				b.WriteString(html.EscapeString(lines[i]) + "\n")
+			}
+			b.WriteString("</code></pre>\n")
+		case trimmed == "":
+			flush()
+		case markdownHeadingPattern.MatchString(trimmed):
+			flush()
+			match := markdownHeadingPattern.FindStringSubmatch(trimmed)
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", len(match[1]), inlineHTML(match[2]), len(match[1]))
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			flush()
+			b.WriteString("<hr>\n")
+		case strings.HasPrefix(trimmed, ">"):
+			if len(quote) == 0 {
+				flush()
+			}
+			quote = append(quote, strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "+ "):
+			listItem(&b, &list, "ul", trimmed[2:], flush)
+		case markdownOrderedPattern.MatchString(trimmed):
+			listItem(&b, &list, "ol", markdownOrderedPattern.ReplaceAllString(trimmed, ""), flush)
+		default:
+			if len(paragraph) == 0 {
+				flush()
+			}
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// listItem writes one item, opening a list of kind tag first unless one is
+// already open.
+func listItem(b *strings.Builder, list *string, tag, text string, flush func()) {
+	if *list != tag {
+		flush()
+		fmt.Fprintf(b, "<%s>\n", tag)
+		*list = tag
+	}
+	fmt.Fprintf(b, "<li>%s</li>\n", inlineHTML(strings.TrimSpace(text)))
+}
+
+// inlineHTML escapes text and renders code spans, images, links, bold and
+// italics. Code spans are left alone otherwise.
+func inlineHTML(text string) string {
+	parts := strings.Split(text, "`")
+	var b strings.Builder
+	for i, part := range parts {
+		switch {
+		case i%2 == 1 && i < len(parts)-1:
+			b.WriteString("<code>" + html.EscapeString(part) + "</code>")
+		case i%2 == 1:
+			// Unmatched backtick
+			b.WriteString("`" + inlineSpans(part))
+		default:
+			b.WriteString(inlineSpans(part))
+		}
+	}
+	return b.String()
+}
+
+func inlineSpans(text string) string {
+	text = html.EscapeString(text)
+	text = markdownImagePattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := markdownImagePattern.FindStringSubmatch(match)
+		return fmt.Sprintf(`<img src="%s" alt="%s">`, safeURL(groups[2]), groups[1])
+	})
+	text = markdownLinkHTML.ReplaceAllStringFunc(text, func(match string) string {
+		groups := markdownLinkHTML.FindStringSubmatch(match)
+		return fmt.Sprintf(`<a href="%s">%s</a>`, safeURL(groups[2]), groups[1])
+	})
+	text = markdownBoldPattern.ReplaceAllString(text, "<strong>$1</strong>")
+	return markdownItalicPattern.ReplaceAllString(text, "$1<em>$2</em>$3")
+}
+
+// safeURL drops script URLs from links in rendered ADRs.
+func safeURL(url string) string {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(url)), "javascript:") {
+		return "#"
+	}
+	return url
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestMarkdownToHTML(t *testing.T) {
+	src := "# ADR 001: Use <Go>\n\n**Status**: Accepted  \nsecond line\n\n## Relations\n\n- Depends on: [ADR 002](adr-002-x.md)\n- Uses `a_b*c`\n\n1. first\n2. _second_\n\n> quoted\n> text\n\n---\n\n```\n<code>\n```\n[bad](javascript:alert(1))\n"
+	expected := "<h1>ADR 001: Use &lt;Go&gt;</h1>\n" +
+		"<p><strong>Status</strong>: Accepted second line</p>\n" +
+		"<h2>Relations</h2>\n" +
+		"<ul>\n<li>Depends on: <a href=\"adr-002-x.md\">ADR 002</a></li>\n<li>Uses <code>a_b*c</code></li>\n</ul>\n" +
+		"<ol>\n<li>first</li>\n<li><em>second</em></li>\n</ol>\n" +
+		"<blockquote><p>quoted text</p></blockquote>\n" +
+		"<hr>\n" +
+		"<pre><code>&lt;code&gt;\n</code></pre>\n" +
+		"<p><a href=\"#\">bad</a>)</p>\n"
+	if got := markdownToHTML(src); got != expected {
+		t.Errorf("markdownToHTML() =\n%s\nwant:\n%s", got, expected)
+	}
+}
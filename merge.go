@@ -0,0 +1,235 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// supersededLabel is the status written to a superseded ADR, with {{new}}
+// standing for the number of the ADR that supersedes it, e.g.
+// "Superseded by ADR-{{new}}". getCurrentStatus reads it back as Superseded.
+var supersededLabel = "Superseded"
+
+func validateSupersededLabel(label string) error {
+	if rendered := supersededStatus(label, "001"); rendered != "Superseded" && !supersededStatusPattern.MatchString(rendered) {
+		return fmt.Errorf("%q must be Superseded or start with \"Superseded by\" to be read back as Superseded", label)
+	}
+	return nil
+}
+
+// supersededStatus is the status of an ADR superseded by ADR number.
+func supersededStatus(label, number string) string {
+	return renderTemplateVars(label, map[string]string{"new": number})
+}
+
+// footerRule returns the index of the "---" rule that starts the footer of
+// lines: the last rule after the header region with no heading after it.
+// The rule templates put under the metadata is part of the header. It
+// returns -1 when there is no footer.
+func footerRule(lines []string) int {
+	rule, inFence := -1, false
+	for i := headerEnd(lines); i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inFence = !inFence
+		case inFence:
+		case trimmed == "---":
+			rule = i
+		case markdownHeadingLine.MatchString(trimmed):
+			rule = -1
+		}
+	}
+	return rule
+}
+
+// mergedBody is what of an ADR is carried into the one it is merged into:
+// its sections, one heading level down, without the title, the metadata
+// fields and the rule under them, the Relations section or the footer.
+func mergedBody(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if end := frontmatterEnd(lines); end > 0 {
+		lines = lines[end+1:]
+	}
+	for _, name := range []string{"Title", "Status", "Previous Status", "Date"} {
+		lines = removeFields(lines, name)
+	}
+	if rule := footerRule(lines); rule >= 0 {
+		lines = lines[:rule]
+	}
+
+	var body []string
+	header := headerEnd(lines)
+	skipping, inFence := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		} else if !inFence {
+			if trimmed == "---" && i < header {
+				continue
+			}
+			if strings.HasPrefix(line, "# ") {
+				continue
+			}
+			if strings.HasPrefix(line, "## ") {
+				skipping = isRelationsHeading(trimmed)
+			}
+			if markdownHeadingLine.MatchString(line) && !strings.HasPrefix(line, "######") {
+				line = "#" + line
+			}
+		}
+		if !skipping {
+			body = append(body, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(body, "\n"))
+}
+
+// insertMergedSection adds section before the Relations section of content,
+// or before its footer rule, or at the end.
+func insertMergedSection(content, section string) string {
+	lines := strings.Split(content, "\n")
+	insertAt := len(lines)
+	if rule := footerRule(lines); rule >= 0 {
+		insertAt = rule
+	}
+	for i := headerEnd(lines); i < insertAt; i++ {
+		if isRelationsHeading(strings.TrimSpace(lines[i])) {
+			insertAt = i
+			break
+		}
+	}
+
+	before := strings.TrimRight(strings.Join(lines[:insertAt], "\n"), "\n")
+	if insertAt == len(lines) {
+		return before + "\n\n" + section + "\n"
+	}
+	return before + "\n\n" + section + "\n\n" + strings.Join(lines[insertAt:], "\n")
+}
+
+// planMerge works out the edits for merging ADR from into ADR into: into
+// gains from's body and a Supersedes relation, from becomes Superseded by
+// into, and references to from elsewhere are redirected to into.
+func planMerge(into, from string) ([]archiveEdit, error) {
+	active, err := listADRFiles("")
+	if err != nil {
+		return nil, err
+	}
+	archived, err := listADRFiles(archiveDir)
+	if err != nil {
+		return nil, err
+	}
+
+	byNumber := make(map[string]string)
+	for _, name := range active {
+		if num, ok := parseADRNumber(name); ok {
+			byNumber[formatNumber(num)] = name
+		}
+	}
+	intoFile, ok := byNumber[into]
+	if !ok {
+		return nil, fmt.Errorf("ADR %s not found in %s", into, adrDir)
+	}
+	fromFile, ok := byNumber[from]
+	if !ok {
+		return nil, fmt.Errorf("ADR %s not found in %s", from, adrDir)
+	}
+
+	var edits []archiveEdit
+	for _, name := range append(active, archived...) {
+		content, err := readFileWithRetry(filepath.Join(adrDir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		var updated string
+		switch name {
+		case intoFile:
+			source, err := readFileWithRetry(filepath.Join(adrDir, fromFile))
+			if err != nil {
+				return nil, err
+			}
+			section := fmt.Sprintf("## Merged from ADR %s: %s", from, getCurrentTitle(string(source)))
+			if body := mergedBody(string(source)); body != "" {
+				section += "\n\n" + body
+			}
+			updated = insertMergedSection(string(content), section)
+			if relation := (Relation{Type: "Supersedes", TargetNumber: from}); !hasRelation(updated, relation) {
+				updated = writeRelation(updated, relation, fromFile)
+			}
+		case fromFile:
+			updated = string(content)
+			if getCurrentStatus(updated) != "Superseded" {
+				updated = updateStatus(updated, supersededStatus(supersededLabel, into))
+			}
+			if relation := (Relation{Type: "Superseded by", TargetNumber: into}); !hasRelation(updated, relation) {
+				updated = writeRelation(updated, relation, intoFile)
+			}
+		default:
+			updated = renumberReferences(string(content), map[string]string{from: into}, map[string]string{fromFile: intoFile})
+		}
+		if updated != string(content) {
+			edits = append(edits, archiveEdit{File: name, Content: updated})
+		}
+	}
+	return edits, nil
+}
+
+func runMergeCommand(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	registerDirFlags(fs)
+	into := fs.String("into", "", "Number of the ADR the other one is merged into")
+	dryRun := fs.Bool("dry-run", false, "Print the changes without applying them")
+	fs.StringVar(&supersededLabel, "superseded-label", supersededLabel, "Status written to the merged ADR, with {{new}} for the number it was merged into, e.g. \"Superseded by ADR-{{new}}\"")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if err := validateSupersededLabel(supersededLabel); err != nil {
+		return fmt.Errorf("invalid --superseded-label: %v", err)
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected the number of the ADR to merge, e.g. adrgen merge --into 005 006")
+	}
+	from := fs.Arg(0)
+	if err := validateNumber(*into); err != nil {
+		return fmt.Errorf("invalid --into: %v", err)
+	}
+	if err := validateNumber(from); err != nil {
+		return fmt.Errorf("invalid ADR number: %v", err)
+	}
+	if *into == from {
+		return fmt.Errorf("an ADR cannot be merged into itself")
+	}
+
+	edits, err := planMerge(*into, from)
+	if err != nil {
+		return err
+	}
+	for _, edit := range edits {
+		switch num, _ := parseADRNumber(path.Base(edit.File)); formatNumber(num) {
+		case *into:
+			fmt.Printf("%s: appended ADR %s\n", edit.File, from)
+		case from:
+			fmt.Printf("%s: marked Superseded by ADR %s\n", edit.File, *into)
+		default:
+			fmt.Printf("%s: redirected references to ADR %s\n", edit.File, *into)
+		}
+	}
+
+	if *dryRun {
+		fmt.Println("Dry run: no files were changed")
+		return nil
+	}
+	for _, edit := range edits {
+		target := filepath.Join(adrDir, filepath.FromSlash(edit.File))
+		if err := withRetry(func() error { return writeFile(target, edit.Content) }); err != nil {
+			return err
+		}
+	}
+	return withRetry(updateIndex)
+}
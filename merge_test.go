@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanMerge(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"adr-005-use-kafka.md":         "# ADR 005: Use Kafka\n\n**Status**: Accepted  \n\n## Decision\n\nKafka.\n\n## Relations\n\n- Related to: ADR 003\n",
+		"adr-006-use-event-bus.md":     "# ADR 006: Use an Event Bus\n\n**Status**: Accepted  \n**Date**: 2024-01-01\n\n## Context\n\nWe need events.\n\n### Options\n\nMany.\n\n## Relations\n\n- Related to: ADR 005\n\n---\n\n_footer_\n",
+		"adr-007-consumers.md":         "# ADR 007: Consumers\n\n## Relations\n\n- Depends on: [ADR 006](adr-006-use-event-bus.md)\n",
+		"archive/adr-001-old-queue.md": "# ADR 001: Old Queue\n\n## Relations\n\n- Replaced by: ADR 006\n",
+	}
+	for file, content := range testFiles {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(tempDir, file)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	edits, err := planMerge("005", "006")
+	if err != nil {
+		t.Fatalf("planMerge() failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, edit := range edits {
+		got[edit.File] = edit.Content
+	}
+
+	expected := map[string]string{
+		"adr-005-use-kafka.md": "# ADR 005: Use Kafka\n\n**Status**: Accepted  \n\n## Decision\n\nKafka.\n\n" +
+			"## Merged from ADR 006: Use an Event Bus\n\n### Context\n\nWe need events.\n\n#### Options\n\nMany.\n\n" +
+			"## Relations\n\n- Related to: ADR 003\n- Supersedes: [ADR 006](adr-006-use-event-bus.md)\n",
+		"adr-007-consumers.md":         "# ADR 007: Consumers\n\n## Relations\n\n- Depends on: [ADR 005](adr-005-use-kafka.md)\n",
+		"archive/adr-001-old-queue.md": "# ADR 001: Old Queue\n\n## Relations\n\n- Replaced by: ADR 005\n",
+	}
+	for file, want := range expected {
+		if got[file] != want {
+			t.Errorf("%s = %q, want %q", file, got[file], want)
+		}
+	}
+
+	source := got["adr-006-use-event-bus.md"]
+	if getCurrentStatus(source) != "Superseded" || !hasRelation(source, Relation{Type: "Superseded by", TargetNumber: "005"}) {
+		t.Errorf("merged ADR = %q, want it Superseded by ADR 005", source)
+	}
+
+	if _, err := planMerge("005", "009"); err == nil {
+		t.Error("planMerge() accepted a missing ADR")
+	}
+}
+
+func TestPlanMergeSupersededLabel(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalLabel := adrDir, supersededLabel
+	adrDir, supersededLabel = tempDir, "Superseded by ADR-{{new}}"
+	defer func() { adrDir, supersededLabel = originalAdrDir, originalLabel }()
+
+	for file, content := range map[string]string{
+		"adr-005-use-kafka.md":     "# ADR 005: Use Kafka\n\n**Status**: Accepted  \n",
+		"adr-006-use-event-bus.md": "# ADR 006: Use an Event Bus\n\n**Status**: Accepted  \n",
+	} {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	edits, err := planMerge("005", "006")
+	if err != nil {
+		t.Fatalf("planMerge() failed: %v", err)
+	}
+	var source string
+	for _, edit := range edits {
+		if edit.File == "adr-006-use-event-bus.md" {
+			source = edit.Content
+		}
+	}
+	if !strings.Contains(source, "**Status**: Superseded by ADR-005") {
+		t.Errorf("merged ADR = %q, want status Superseded by ADR-005", source)
+	}
+	if status := getCurrentStatus(source); status != "Superseded" {
+		t.Errorf("getCurrentStatus() = %q, want Superseded", status)
+	}
+}
+
+func TestCanonicalStatus(t *testing.T) {
+	tests := map[string]string{
+		"Superseded":                          "Superseded",
+		"Superseded by ADR-012":               "Superseded",
+		"superseded by [ADR 3](adr-003-x.md)": "Superseded",
+		"Superseded  by 12":                   "Superseded",
+		"Supersededby 12":                     "Supersededby 12",
+		"Accepted":                            "Accepted",
+		"Not superseded by ADR 4":             "Not superseded by ADR 4",
+	}
+	for status, want := range tests {
+		if got := canonicalStatus(status); got != want {
+			t.Errorf("canonicalStatus(%q) = %q, want %q", status, got, want)
+		}
+	}
+
+	for _, label := range []string{"Superseded", "Superseded by ADR-{{new}}"} {
+		if err := validateSupersededLabel(label); err != nil {
+			t.Errorf("validateSupersededLabel(%q) = %v", label, err)
+		}
+	}
+	if err := validateSupersededLabel("Replaced by {{new}}"); err == nil {
+		t.Error("validateSupersededLabel() accepted a label not read back as Superseded")
+	}
+}
+
+func TestPlanMergeDefaultTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	for _, adr := range []struct{ number, title string }{{"005", "Use Kafka"}, {"006", "Use an Event Bus"}} {
+		content := renderTemplateVars(defaultTemplate, map[string]string{"number": adr.number, "title": adr.title, "status": "Accepted", "date": "2024-01-01"})
+		if err := writeFile(filepath.Join(tempDir, adrFilename(adr.number, adr.title)), content); err != nil {
+			t.Fatalf("Failed to create ADR %s: %v", adr.number, err)
+		}
+	}
+
+	edits, err := planMerge("005", "006")
+	if err != nil {
+		t.Fatalf("planMerge() failed: %v", err)
+	}
+	var into string
+	for _, edit := range edits {
+		if edit.File == adrFilename("005", "Use Kafka") {
+			into = edit.Content
+		}
+	}
+
+	merged := strings.Index(into, "## Merged from ADR 006: Use an Event Bus\n\n### Context\n\nDescribe here")
+	if merged < 0 {
+		t.Fatalf("merged ADR = %q, want ADR 006's sections under the merged heading", into)
+	}
+	for _, heading := range []string{"## Context", "## Consequences"} {
+		if at := strings.Index(into, heading); at < 0 || at > merged {
+			t.Errorf("%s at %d, want it before the merged section at %d", heading, at, merged)
+		}
+	}
+	if relations := strings.Index(into, "## Relations"); relations < merged {
+		t.Errorf("## Relations at %d, want it after the merged section at %d", relations, merged)
+	}
+	if strings.Count(into[merged:], "### Relations") != 0 || strings.Count(into, "**Status**") != 1 {
+		t.Errorf("merged ADR = %q, want ADR 006's metadata and relations left out", into)
+	}
+}
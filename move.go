@@ -0,0 +1,192 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// relationReferencePattern matches what a renumbering has to follow in a
+// Relations line: a link target, a quoted filename, or an "ADR NNN" style
+// number.
+var relationReferencePattern = regexp.MustCompile(`\]\(([^)\s]+\.md)\)|'([^'\s]+\.md)'|\b((?i:adr)[-_ ]?)(\d+|[IVXLCDM]+\b)`)
+
+// adrRename is an ADR file getting a new number, as paths relative to the
+// ADR directory.
+type adrRename struct {
+	From   string
+	To     string
+	Number string
+}
+
+// renumberReferences rewrites the Relations references of content in a single
+// pass, so swapped numbers don't get mapped twice. numbers maps old to new
+// numbers; files maps old to new base filenames.
+func renumberReferences(content string, numbers, files map[string]string) string {
+	lines := strings.Split(content, "\n")
+	forEachRelationLine(content, func(lineNumber int, _ string) {
+		i := lineNumber - 1
+		lines[i] = relationReferencePattern.ReplaceAllStringFunc(lines[i], func(match string) string {
+			groups := relationReferencePattern.FindStringSubmatch(match)
+			if target := groups[1] + groups[2]; target != "" {
+				newBase, ok := files[path.Base(target)]
+				if !ok {
+					return match
+				}
+				return strings.Replace(match, target, path.Join(path.Dir(target), newBase), 1)
+			}
+			num, ok := parseNumber(groups[4])
+			if !ok {
+				return match
+			}
+			if number, ok := numbers[formatNumber(num)]; ok {
+				return groups[3] + number
+			}
+			return match
+		})
+	})
+	return strings.Join(lines, "\n")
+}
+
+// planMove works out the renames and file contents for giving ADR from the
+// number to. With swap, an ADR already numbered to takes from's number.
+func planMove(from, to string, swap bool) ([]adrRename, []archiveEdit, error) {
+	active, err := listADRFiles("")
+	if err != nil {
+		return nil, nil, err
+	}
+	archived, err := listADRFiles(archiveDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	all := append(active, archived...)
+
+	byNumber := make(map[string]string)
+	for _, name := range all {
+		if num, ok := parseADRNumber(path.Base(name)); ok {
+			byNumber[formatNumber(num)] = name
+		}
+	}
+	fromFile, ok := byNumber[from]
+	if !ok {
+		return nil, nil, fmt.Errorf("ADR %s not found in %s", from, adrDir)
+	}
+	numbers := map[string]string{from: to}
+	renames := []adrRename{{From: fromFile, Number: to}}
+	if toFile, taken := byNumber[to]; taken {
+		if !swap {
+			return nil, nil, fmt.Errorf("ADR %s already exists (%s); use --swap to exchange the numbers", to, toFile)
+		}
+		numbers[to] = from
+		renames = append(renames, adrRename{From: toFile, Number: from})
+	}
+
+	files := make(map[string]string)
+	renamed := make(map[string]*adrRename)
+	for i := range renames {
+		r := &renames[i]
+		base := path.Base(r.From)
+		newBase := renderFilename(r.Number, extractTitleFromFilename(base), base)
+		r.To = path.Join(path.Dir(r.From), newBase)
+		files[base] = newBase
+		renamed[r.From] = r
+	}
+
+	var edits []archiveEdit
+	for _, name := range all {
+		content, err := readFileWithRetry(filepath.Join(adrDir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, nil, err
+		}
+		updated := renumberReferences(string(content), numbers, files)
+		target := name
+		if r, ok := renamed[name]; ok {
+			if title := getCurrentTitle(updated); getHeadingLine(updated) != "" && title != "" {
+				updated = setHeading(updated, r.Number, title)
+			}
+			target = r.To
+		}
+		if updated != string(content) || target != name {
+			edits = append(edits, archiveEdit{File: target, Content: updated})
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].File < edits[j].File })
+	return renames, edits, nil
+}
+
+// applyMove writes the edits and then removes the old files of renamed ADRs,
+// unless a swap reused the name. Companion files are renamed along.
+func applyMove(renames []adrRename, edits []archiveEdit) error {
+	written := make(map[string]bool)
+	for _, edit := range edits {
+		target := filepath.Join(adrDir, filepath.FromSlash(edit.File))
+		if err := withRetry(func() error { return writeFile(target, edit.Content) }); err != nil {
+			return err
+		}
+		written[edit.File] = true
+	}
+	companionMoves := make(map[string]string)
+	for _, r := range renames {
+		source := filepath.Join(adrDir, filepath.FromSlash(r.From))
+		companionMoves[source] = filepath.Join(adrDir, filepath.FromSlash(r.To))
+		if written[r.From] {
+			continue
+		}
+		if err := withRetry(func() error { return fsys.Remove(source) }); err != nil {
+			return err
+		}
+		debugf("removed %s", source)
+	}
+	return moveCompanions(companionMoves)
+}
+
+func runMoveCommand(args []string) error {
+	fs := flag.NewFlagSet("move", flag.ContinueOnError)
+	registerDirFlags(fs)
+	from := fs.String("from", "", "Current number of the ADR")
+	to := fs.String("to", "", "New number for the ADR")
+	swap := fs.Bool("swap", false, "When the new number is taken, give that ADR the old number")
+	dryRun := fs.Bool("dry-run", false, "Print the changes without applying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if err := validateNumber(*from); err != nil {
+		return fmt.Errorf("invalid --from: %v", err)
+	}
+	if err := validateNumber(*to); err != nil {
+		return fmt.Errorf("invalid --to: %v", err)
+	}
+	if *from == *to {
+		return fmt.Errorf("--from and --to are the same number")
+	}
+
+	renames, edits, err := planMove(*from, *to, *swap)
+	if err != nil {
+		return err
+	}
+
+	renamedTo := make(map[string]bool)
+	for _, r := range renames {
+		fmt.Printf("%s -> %s\n", r.From, r.To)
+		renamedTo[r.To] = true
+	}
+	for _, edit := range edits {
+		if !renamedTo[edit.File] {
+			fmt.Printf("%s: updated relation references\n", edit.File)
+		}
+	}
+
+	if *dryRun {
+		fmt.Println("Dry run: no files were changed")
+		return nil
+	}
+	if err := applyMove(renames, edits); err != nil {
+		return err
+	}
+	return withRetry(updateIndex)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMove(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"adr-001-use-rabbitmq.md":      "# ADR 001: Use RabbitMQ\n\n**Status**: Accepted  \n\n## Relations\n\n- Related to: [ADR 002](adr-002-use-json.md)\n",
+		"adr-002-use-json.md":          "# ADR 002: Use JSON\n\n**Status**: Accepted  \n\nSee ADR 001 for context.\n\n## Relations\n\n- Depends on: ADR 001\n",
+		"archive/adr-003-use-kafka.md": "# ADR 003: Use Kafka\n\n**Status**: Superseded  \n\n## Relations\n\n- Related to: [ADR 002](../adr-002-use-json.md), adr-001\n",
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, archiveDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, filepath.FromSlash(file)), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if _, _, err := planMove("001", "002", false); err == nil {
+		t.Error("planMove() to a taken number should fail without swap")
+	}
+
+	renames, edits, err := planMove("001", "002", true)
+	if err != nil {
+		t.Fatalf("planMove() failed: %v", err)
+	}
+	if len(renames) != 2 {
+		t.Fatalf("planMove() renames = %+v, want ADRs 001 and 002", renames)
+	}
+	if err := applyMove(renames, edits); err != nil {
+		t.Fatalf("applyMove() failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"adr-002-use-rabbitmq.md":      "# ADR 002: Use RabbitMQ\n\n**Status**: Accepted  \n\n## Relations\n\n- Related to: [ADR 001](adr-001-use-json.md)\n",
+		"adr-001-use-json.md":          "# ADR 001: Use JSON\n\n**Status**: Accepted  \n\nSee ADR 001 for context.\n\n## Relations\n\n- Depends on: ADR 002\n",
+		"archive/adr-003-use-kafka.md": "# ADR 003: Use Kafka\n\n**Status**: Superseded  \n\n## Relations\n\n- Related to: [ADR 001](../adr-001-use-json.md), adr-002\n",
+	}
+	for file, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(file)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s = %q, want %q", file, content, want)
+		}
+	}
+	for _, file := range []string{"adr-001-use-rabbitmq.md", "adr-002-use-json.md"} {
+		if _, err := os.Stat(filepath.Join(tempDir, file)); !os.IsNotExist(err) {
+			t.Errorf("%s was left behind", file)
+		}
+	}
+
+	if _, _, err := planMove("009", "010", false); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("planMove() of a missing ADR error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// numberFormat names the numbering scheme ADR numbers are written in:
+// "padded" (zero-padded to --number-width, the default), "decimal" or
+// "roman".
+var numberFormat = "padded"
+
+// numberFormatter writes ADR numbers in one numbering scheme and reads them
+// back, so lookups, sorting and allocation work on plain integers whatever
+// the scheme.
+type numberFormatter interface {
+	Format(num int) string
+	Parse(s string) (int, bool)
+	// Pattern is a regular expression matching formatted numbers.
+	Pattern() string
+}
+
+var numberFormatters = map[string]numberFormatter{
+	"padded":  paddedNumbers{},
+	"decimal": decimalNumbers{},
+	"roman":   romanNumbers{},
+}
+
+var numberFormatNames = []string{"padded", "decimal", "roman"}
+
+func activeNumberFormatter() numberFormatter {
+	if formatter, ok := numberFormatters[numberFormat]; ok {
+		return formatter
+	}
+	return paddedNumbers{}
+}
+
+func formatNumber(num int) string {
+	return activeNumberFormatter().Format(num)
+}
+
+// parseNumber reads an ADR number written in the active scheme.
+func parseNumber(s string) (int, bool) {
+	return activeNumberFormatter().Parse(strings.TrimSpace(s))
+}
+
+type paddedNumbers struct{}
+
+func (paddedNumbers) Format(num int) string {
+	return fmt.Sprintf("%0*d", numberWidth, num)
+}
+
+func (paddedNumbers) Parse(s string) (int, bool) {
+	return decimalNumbers{}.Parse(s)
+}
+
+func (paddedNumbers) Pattern() string {
+	return `\d+`
+}
+
+type decimalNumbers struct{}
+
+func (decimalNumbers) Format(num int) string {
+	return strconv.Itoa(num)
+}
+
+func (decimalNumbers) Parse(s string) (int, bool) {
+	num, err := strconv.Atoi(s)
+	return num, err == nil && num >= 0
+}
+
+func (decimalNumbers) Pattern() string {
+	return `\d+`
+}
+
+// romanNumbers writes 1 to 3999 as uppercase Roman numerals; numbers outside
+// that range fall back to decimal.
+type romanNumbers struct{}
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"},
+	{50, "L"}, {40, "XL"}, {10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+func (romanNumbers) Format(num int) string {
+	if num < 1 || num > 3999 {
+		return strconv.Itoa(num)
+	}
+	var b strings.Builder
+	for _, numeral := range romanNumerals {
+		for ; num >= numeral.value; num -= numeral.value {
+			b.WriteString(numeral.symbol)
+		}
+	}
+	return b.String()
+}
+
+// Parse accepts only numerals in their canonical form, so "IIII" or "IC"
+// aren't taken for ADR numbers.
+func (r romanNumbers) Parse(s string) (int, bool) {
+	if num, ok := (decimalNumbers{}).Parse(s); ok {
+		return num, true
+	}
+	num, rest := 0, strings.ToUpper(s)
+	for _, numeral := range romanNumerals {
+		for strings.HasPrefix(rest, numeral.symbol) {
+			num += numeral.value
+			rest = rest[len(numeral.symbol):]
+		}
+	}
+	if s == "" || rest != "" || r.Format(num) != strings.ToUpper(s) {
+		return 0, false
+	}
+	return num, true
+}
+
+func (romanNumbers) Pattern() string {
+	return `\d+|[IVXLCDMivxlcdm]+`
+}
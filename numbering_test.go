@@ -0,0 +1,87 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNumberFormatters(t *testing.T) {
+	originalFormat, originalWidth := numberFormat, numberWidth
+	defer func() { numberFormat, numberWidth = originalFormat, originalWidth }()
+	numberWidth = 3
+
+	tests := []struct {
+		format string
+		num    int
+		text   string
+	}{
+		{"padded", 7, "007"},
+		{"decimal", 7, "7"},
+		{"roman", 7, "VII"},
+		{"roman", 1994, "MCMXCIV"},
+		{"roman", 4000, "4000"},
+	}
+	for _, tt := range tests {
+		numberFormat = tt.format
+		if text := formatNumber(tt.num); text != tt.text {
+			t.Errorf("%s formatNumber(%d) = %q, want %q", tt.format, tt.num, text, tt.text)
+		}
+		if num, ok := parseNumber(tt.text); !ok || num != tt.num {
+			t.Errorf("%s parseNumber(%q) = %d, %v, want %d", tt.format, tt.text, num, ok, tt.num)
+		}
+	}
+
+	numberFormat = "roman"
+	for _, invalid := range []string{"IIII", "IC", "VX", "ADR", ""} {
+		if num, ok := parseNumber(invalid); ok {
+			t.Errorf("roman parseNumber(%q) = %d, want no number", invalid, num)
+		}
+	}
+	if err := validateNumber("XII"); err != nil {
+		t.Errorf("validateNumber(XII) failed: %v", err)
+	}
+	if err := validateNumber("12"); err == nil {
+		t.Error("validateNumber(12) accepted a decimal number with --number-format roman")
+	}
+}
+
+func TestRomanNumbering(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalFormat := adrDir, numberFormat
+	adrDir = tempDir
+	defer func() { adrDir, numberFormat = originalAdrDir, originalFormat }()
+
+	numberFormat = "roman"
+	for file, content := range map[string]string{
+		"adr-II-use-go.md":  "# ADR II: Use Go\n\n## Relations\n\n- Depends on: ADR IX\n",
+		"adr-IX-use-vim.md": "# ADR IX: Use Vim\n",
+		"adr-X-use-git.md":  "# ADR X: Use Git\n",
+	} {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if next := getNextADRNumber(); next != "XI" {
+		t.Errorf("getNextADRNumber() = %q, want XI", next)
+	}
+	if !adrExists("IX") || adrExists("XI") {
+		t.Error("adrExists() doesn't look ADRs up by Roman number")
+	}
+	relations := parseRelations("## Relations\n\n- Depends on: ADR IX\n- Related to: ADR Decision\n")
+	if len(relations) != 1 || relations[0].TargetNumber != "IX" {
+		t.Errorf("parseRelations() = %+v, want a relation to ADR IX", relations)
+	}
+
+	adrs, err := loadADRs()
+	if err != nil {
+		t.Fatalf("loadADRs() failed: %v", err)
+	}
+	var order []string
+	for _, adr := range adrs {
+		order = append(order, adr.Number)
+	}
+	if len(order) != 3 || order[0] != "II" || order[1] != "IX" || order[2] != "X" {
+		t.Errorf("ADR order = %v, want numeric order II, IX, X", order)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// findADRPath looks up an ADR by number in the ADR directory and then in the
+// archive, returning its slash-separated path relative to adrDir.
+func findADRPath(number string) (string, bool, error) {
+	for _, dir := range []string{"", archiveDir} {
+		files, err := readDirWithRetry(filepath.Join(adrDir, dir))
+		if err != nil {
+			if dir == archiveDir && os.IsNotExist(err) {
+				break
+			}
+			return "", false, err
+		}
+		if name, ok := findADRFile(files, number); ok {
+			return path.Join(dir, name), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// publishedURL maps an ADR path onto the published site: the .md extension
+// is swapped for ext, where "/" gives directory-style URLs and "" keeps .md.
+func publishedURL(baseURL, adrPath, ext string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return "", fmt.Errorf("invalid --base-url %q: must be an absolute URL", baseURL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	published := adrPath
+	switch ext {
+	case "":
+	case "/":
+		published = strings.TrimSuffix(published, ".md") + "/"
+	default:
+		published = strings.TrimSuffix(published, ".md") + "." + strings.TrimPrefix(ext, ".")
+	}
+
+	ref, err := url.Parse((&url.URL{Path: published}).EscapedPath())
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+func runOpenCommand(args []string) error {
+	fs := flag.NewFlagSet("open", flag.ContinueOnError)
+	registerDirFlags(fs)
+	baseURL := fs.String("base-url", "", "URL the ADR directory is published at (e.g., https://docs.example.com/adr/)")
+	ext := fs.String("url-ext", ".html", "Extension of published pages replacing .md: \"/\" for directory-style URLs, \"\" to keep .md")
+	printOnly := fs.Bool("print", false, "Only print the URL instead of also opening it")
+	number, flagArgs := "", args
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		number, flagArgs = args[0], args[1:]
+	}
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if number == "" && fs.NArg() == 1 {
+		number = fs.Arg(0)
+	} else if fs.NArg() > 0 || number == "" {
+		return fmt.Errorf("usage: adrgen open <number> --base-url <url>")
+	}
+	if *baseURL == "" {
+		return fmt.Errorf("--base-url is required")
+	}
+	if err := validateNumber(number); err != nil {
+		return err
+	}
+
+	adrPath, found, err := findADRPath(number)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("ADR %s not found in %s", number, adrDir)
+	}
+	link, err := publishedURL(*baseURL, adrPath, *ext)
+	if err != nil {
+		return err
+	}
+	fmt.Println(link)
+	if *printOnly {
+		return nil
+	}
+	return openFile(link)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPublishedURL(t *testing.T) {
+	tests := []struct {
+		base, path, ext string
+		expected        string
+	}{
+		{"https://docs.example.com/adr/", "adr-007-use-redis.md", ".html", "https://docs.example.com/adr/adr-007-use-redis.html"},
+		{"https://docs.example.com/adr", "adr-007-use-redis.md", "/", "https://docs.example.com/adr/adr-007-use-redis/"},
+		{"https://docs.example.com/adr/", "archive/adr-001-use go.md", "", "https://docs.example.com/adr/archive/adr-001-use%20go.md"},
+	}
+	for _, test := range tests {
+		result, err := publishedURL(test.base, test.path, test.ext)
+		if err != nil {
+			t.Errorf("publishedURL(%q, %q, %q) failed: %v", test.base, test.path, test.ext, err)
+		} else if result != test.expected {
+			t.Errorf("publishedURL(%q, %q, %q) = %q, want %q", test.base, test.path, test.ext, result, test.expected)
+		}
+	}
+
+	if _, err := publishedURL("docs/adr", "adr-007-use-redis.md", ".html"); err == nil {
+		t.Error("publishedURL() should reject a relative base URL")
+	}
+}
+
+func TestFindADRPath(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalTemplate := adrDir, filenameTemplate
+	adrDir, filenameTemplate = tempDir, "{{year}}-{{number}}-{{slug}}.md"
+	defer func() { adrDir, filenameTemplate = originalAdrDir, originalTemplate }()
+
+	for _, file := range []string{"2024-007-use-redis.md", filepath.Join(archiveDir, "2019-001-use-rabbitmq.md")} {
+		if err := ensureDir(filepath.Dir(filepath.Join(tempDir, file))); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := writeFile(filepath.Join(tempDir, file), "test content"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	for number, expected := range map[string]string{"007": "2024-007-use-redis.md", "001": "archive/2019-001-use-rabbitmq.md"} {
+		if result, found, err := findADRPath(number); err != nil || !found || result != expected {
+			t.Errorf("findADRPath(%q) = %q, %v, %v, want %q", number, result, found, err, expected)
+		}
+	}
+	if _, found, _ := findADRPath("002"); found {
+		t.Error("findADRPath() found a missing ADR")
+	}
+}
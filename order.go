@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// orderFile pins the index order: one ADR number or filename per line, in
+// reading order. Blank lines and # comments are skipped.
+const orderFile = ".adr-order"
+
+// loadIndexOrder returns the entries of the order file, or nil when the ADR
+// directory has none.
+func loadIndexOrder() ([]string, error) {
+	content, err := readFileWithRetry(filepath.Join(adrDir, orderFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			entries = append(entries, line)
+		}
+	}
+	return entries, nil
+}
+
+// orderADRs puts adrs in the order of entries, with the ADRs the order file
+// doesn't list after them in their current order.
+func orderADRs(adrs []ADR, entries []string) []ADR {
+	byFile := make(map[string]int, len(adrs))
+	byNumber := make(map[string]int, len(adrs))
+	for i, adr := range adrs {
+		byFile[adr.Filename] = i
+		if adr.Number != "" {
+			byNumber[adr.Number] = i
+		}
+	}
+
+	ordered := make([]ADR, 0, len(adrs))
+	placed := make(map[int]bool, len(adrs))
+	for _, entry := range entries {
+		i, ok := byFile[entry]
+		if !ok {
+			if num, valid := parseNumber(entry); valid {
+				i, ok = byNumber[formatNumber(num)]
+			}
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Warning: %s lists %q, which is not an ADR in %s\n", orderFile, entry, adrDir)
+			continue
+		}
+		if placed[i] {
+			continue
+		}
+		placed[i] = true
+		ordered = append(ordered, adrs[i])
+	}
+
+	var unlisted []string
+	for i, adr := range adrs {
+		if !placed[i] {
+			ordered = append(ordered, adr)
+			unlisted = append(unlisted, adr.Filename)
+		}
+	}
+	if len(unlisted) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d ADR(s) not listed in %s were added at the end of the index: %s\n", len(unlisted), orderFile, strings.Join(unlisted, ", "))
+	}
+	return ordered
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// planFormats are the --format values of a --dry-run plan.
+var planFormats = []string{"text", "json"}
+
+// fileOp is one change a run makes to the filesystem: create, update or
+// remove Path, rename From to Path, or write-index. Runs plan every op before
+// applying any, so --dry-run can report them without touching anything.
+type fileOp struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	From string `json:"from,omitempty"`
+
+	apply func() error
+}
+
+// warnOnFailure turns a failure of op into a warning, for steps that
+// shouldn't stop the rest of the run.
+func warnOnFailure(op fileOp, warning string) fileOp {
+	apply := op.apply
+	op.apply = func() error {
+		if err := apply(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", warning, err)
+		}
+		return nil
+	}
+	return op
+}
+
+// applyPlan applies ops in order, stopping at the first that fails.
+func applyPlan(ops []fileOp) error {
+	for _, op := range ops {
+		if err := op.apply(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printPlan reports ops as "op path" lines, or as a JSON array for tools
+// wrapping adrgen.
+func printPlan(ops []fileOp, format string) error {
+	if format == "json" {
+		if ops == nil {
+			ops = []fileOp{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(ops)
+	}
+	for _, op := range ops {
+		if op.From != "" {
+			fmt.Printf("%s %s -> %s\n", op.Op, op.From, op.Path)
+		} else {
+			fmt.Printf("%s %s\n", op.Op, op.Path)
+		}
+	}
+	fmt.Println("Dry run: no files were changed")
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRunCreateDryRunPlan(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	defer func() { adrDir = originalAdrDir }()
+
+	existing := filepath.Join(tempDir, "adr-001-use-go.md")
+	if err := writeFile(existing, "# ADR 001: Use Go\n\n**Status**: Proposed  \n**Date**: 2024-01-01\n"); err != nil {
+		t.Fatalf("Failed to create ADR: %v", err)
+	}
+
+	args := []string{"--dir", tempDir, "--number", "001", "--status", "Accepted", "--title", "Use Rust", "--dry-run", "--format", "json"}
+	var code int
+	stdout, _ := captureOutput(t, func() { code = runCreate(args) })
+	if code != 0 {
+		t.Fatalf("runCreate() = %d, want 0", code)
+	}
+
+	var ops []fileOp
+	if err := json.Unmarshal([]byte(stdout), &ops); err != nil {
+		t.Fatalf("dry-run output is not a JSON plan: %v\n%s", err, stdout)
+	}
+	expected := []fileOp{
+		{Op: "remove", Path: existing},
+		{Op: "create", Path: filepath.Join(tempDir, "adr-001-use-rust.md")},
+		{Op: "write-index", Path: filepath.Join(tempDir, "README.md")},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("plan = %+v, want %+v", ops, expected)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", tempDir, err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dry run changed the directory: %v", entries)
+	}
+
+	args = []string{"--dir", tempDir, "--number", "001", "--status", "Accepted", "--title", "Use Rust", "--dry-run", "--format", "yaml"}
+	captureOutput(t, func() { code = runCreate(args) })
+	if code == 0 {
+		t.Error("runCreate() should reject an unknown --format")
+	}
+}
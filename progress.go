@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+var quiet = false
+
+// progress prints an "N/total" counter on a single stderr line while a
+// command scans the ADR directory. It stays silent with --quiet or when
+// stderr is not a terminal, so piped output and completions are unaffected.
+type progress struct {
+	mu    sync.Mutex
+	out   io.Writer
+	label string
+	total int
+	done  int
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func newProgress(label string, total int) *progress {
+	p := &progress{label: label, total: total}
+	if !quiet && isTerminal(os.Stderr) {
+		p.out = os.Stderr
+	}
+	return p
+}
+
+func (p *progress) Step() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if p.out != nil {
+		fmt.Fprintf(p.out, "\r%s %d/%d", p.label, p.done, p.total)
+	}
+}
+
+// Finish clears the counter line.
+func (p *progress) Finish() {
+	if p.out != nil && p.done > 0 {
+		fmt.Fprintf(p.out, "\r%s\r", strings.Repeat(" ", len(fmt.Sprintf("%s %d/%d", p.label, p.done, p.total))))
+	}
+}
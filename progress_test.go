@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	var out bytes.Buffer
+	p := &progress{out: &out, label: "Reading ADRs", total: 2}
+	p.Step()
+	p.Step()
+	p.Finish()
+
+	expected := "\rReading ADRs 1/2\rReading ADRs 2/2\r                \r"
+	if out.String() != expected {
+		t.Errorf("progress output = %q, want %q", out.String(), expected)
+	}
+
+	// Without a terminal nothing is written
+	silent := newProgress("Reading ADRs", 2)
+	silent.Step()
+	silent.Finish()
+	if silent.out != nil {
+		t.Error("newProgress() should be silent when stderr is not a terminal")
+	}
+}
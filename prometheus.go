@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// prometheusFile is the name the prometheus export target writes in its
+// output directory, for node_exporter's textfile collector.
+const prometheusFile = "adrgen.prom"
+
+// prometheusLabel turns a status into a label value: lowercased, with the
+// backslashes, quotes and newlines the text format requires escaped.
+var prometheusLabel = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// statusCounts counts adrs by lowercased status. The known statuses are
+// always there, so their series don't disappear when they drop to zero.
+func statusCounts(adrs []ADR) map[string]int {
+	counts := make(map[string]int)
+	for _, status := range statuses {
+		counts[strings.ToLower(status)] = 0
+	}
+	for _, adr := range adrs {
+		status := strings.ToLower(strings.TrimSpace(adr.Status))
+		if status == "" {
+			status = "unknown"
+		}
+		counts[status]++
+	}
+	return counts
+}
+
+// renderPrometheus renders the ADR counts of adrs in the Prometheus text
+// exposition format, one adrgen_adr_total sample per status.
+func renderPrometheus(adrs []ADR) string {
+	counts := statusCounts(adrs)
+	names := make([]string, 0, len(counts))
+	for status := range counts {
+		names = append(names, status)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# HELP adrgen_adr_total Number of ADRs by status.\n")
+	b.WriteString("# TYPE adrgen_adr_total gauge\n")
+	for _, status := range names {
+		fmt.Fprintf(&b, "adrgen_adr_total{status=\"%s\"} %d\n", prometheusLabel.Replace(status), counts[status])
+	}
+	return b.String()
+}
+
+func exportPrometheus(adrs []ADR, outDir string) error {
+	if err := ensureDir(outDir); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(outDir, prometheusFile), renderPrometheus(adrs))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderPrometheus(t *testing.T) {
+	adrs := []ADR{
+		{Filename: "adr-001-a.md", Status: "Accepted"},
+		{Filename: "adr-002-b.md", Status: "accepted"},
+		{Filename: "adr-003-c.md", Status: "Proposed"},
+		{Filename: "adr-004-d.md", Status: `On "Hold"\`},
+		{Filename: "adr-005-e.md"},
+	}
+	expected := `# HELP adrgen_adr_total Number of ADRs by status.
+# TYPE adrgen_adr_total gauge
+adrgen_adr_total{status="accepted"} 2
+adrgen_adr_total{status="deprecated"} 0
+adrgen_adr_total{status="on \"hold\"\\"} 1
+adrgen_adr_total{status="proposed"} 1
+adrgen_adr_total{status="rejected"} 0
+adrgen_adr_total{status="superseded"} 0
+adrgen_adr_total{status="unknown"} 1
+`
+	if got := renderPrometheus(adrs); got != expected {
+		t.Errorf("renderPrometheus() = %q, want %q", got, expected)
+	}
+}
+
+func TestExportPrometheus(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := writeFile(filepath.Join(tempDir, "adr-001-use-go.md"), "# ADR 001: Use Go\n\n**Status**: Superseded by ADR-002  \n"); err != nil {
+		t.Fatalf("Failed to create ADR: %v", err)
+	}
+
+	out := filepath.Join(tempDir, "metrics")
+	captureOutput(t, func() {
+		if err := runExportCommand([]string{"--dir", tempDir, "--format", "prometheus", "--out", out}); err != nil {
+			t.Fatalf("runExportCommand() failed: %v", err)
+		}
+	})
+	content, err := os.ReadFile(filepath.Join(out, prometheusFile))
+	if err != nil {
+		t.Fatalf("%s was not written: %v", prometheusFile, err)
+	}
+	if want := "adrgen_adr_total{status=\"superseded\"} 1\n"; !strings.Contains(string(content), want) {
+		t.Errorf("%s = %q, want %q", prometheusFile, content, want)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// markdownLinkPattern matches inline links to Markdown files, with an
+// optional #fragment.
+var markdownLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s#]+\.md)(#[^)\s]*)?\)`)
+
+// publishLinks keeps links to published files and turns every other relative
+// Markdown link into its plain text, so the published subset has no broken
+// links. Links with a scheme are left alone.
+func publishLinks(content string, published map[string]bool) string {
+	return markdownLinkPattern.ReplaceAllStringFunc(content, func(match string) string {
+		groups := markdownLinkPattern.FindStringSubmatch(match)
+		target := groups[2]
+		if strings.Contains(target, "://") || published[path.Clean(target)] {
+			return match
+		}
+		return groups[1]
+	})
+}
+
+// publishADRs writes an index of the ADRs with the wanted statuses to outDir
+// and, unless indexOnly is set, copies them there. ADR files left in outDir
+// from earlier runs that are no longer published are removed.
+func publishADRs(outDir string, wanted []string, indexOnly bool) ([]ADR, error) {
+	if err := ensureDir(outDir); err != nil {
+		return nil, err
+	}
+	if same, _ := sameDir(outDir, adrDir); same {
+		return nil, fmt.Errorf("refusing to publish into the ADR directory %s", adrDir)
+	}
+
+	formatter, err := newIndexFormatter(indexFormat)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := loadIndexHeader(); err != nil {
+		return nil, err
+	}
+	adrs, err := loadADRs()
+	if err != nil {
+		return nil, err
+	}
+	adrs = filterADRsByStatus(adrs, wanted)
+
+	published := make(map[string]bool)
+	if !indexOnly {
+		for _, adr := range adrs {
+			published[adr.Filename] = true
+		}
+		for _, adr := range adrs {
+			content, err := readFileWithRetry(filepath.Join(adrDir, adr.Filename))
+			if err != nil {
+				return nil, err
+			}
+			if _, err := writeFileIfChanged(filepath.Join(outDir, adr.Filename), publishLinks(string(content), published)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	existing, err := readDirWithRetry(outDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range existing {
+		if !file.IsDir() && isADRFile(file.Name()) && !published[file.Name()] {
+			if err := withRetry(func() error { return fsys.Remove(filepath.Join(outDir, file.Name())) }); err != nil {
+				return nil, err
+			}
+			debugf("removed unpublished %s", filepath.Join(outDir, file.Name()))
+		}
+	}
+
+	index := formatter.Format(adrs)
+	if indexOnly {
+		// Without copies, entries link back to the ADR directory
+		index = publishLinks(index, nil)
+	}
+	name := filepath.Base(indexPath(formatter))
+	_, err = writeFileIfChanged(filepath.Join(outDir, name), index)
+	return adrs, err
+}
+
+func runPublishCommand(args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ContinueOnError)
+	registerDirFlags(fs)
+	registerIndexFlags(fs)
+	out := fs.String("out", "public", "Directory to publish the index and ADRs to")
+	status := fs.String("status", "Accepted", "Statuses to publish (comma-separated)")
+	indexOnly := fs.Bool("index-only", false, "Only write the index, listing titles without links, and don't copy ADRs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+
+	var wanted []string
+	for _, s := range strings.Split(*status, ",") {
+		known, ok := normalizeStatus(strings.TrimSpace(s))
+		if !ok {
+			return fmt.Errorf("invalid --status %q (supported: %s)", s, strings.Join(statuses, ", "))
+		}
+		wanted = append(wanted, known)
+	}
+
+	adrs, err := publishADRs(*out, wanted, *indexOnly)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Published %d ADR(s) to %s\n", len(adrs), *out)
+	return nil
+}
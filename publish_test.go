@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPublishLinks(t *testing.T) {
+	published := map[string]bool{"adr-001-use-go.md": true}
+	content := "- Depends on: [ADR 001](adr-001-use-go.md#decision)\n" +
+		"- Replaces: [ADR 002](adr-002-use-java.md)\n" +
+		"- Archived: [ADR 000](archive/adr-000-use-perl.md)\n" +
+		"- See [the guide](https://example.com/guide.md)\n"
+	expected := "- Depends on: [ADR 001](adr-001-use-go.md#decision)\n" +
+		"- Replaces: ADR 002\n" +
+		"- Archived: ADR 000\n" +
+		"- See [the guide](https://example.com/guide.md)\n"
+	if result := publishLinks(content, published); result != expected {
+		t.Errorf("publishLinks() = %q, want %q", result, expected)
+	}
+}
+
+func TestPublishADRs(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = filepath.Join(tempDir, "adr")
+	defer func() { adrDir = originalAdrDir }()
+	if err := ensureDir(adrDir); err != nil {
+		t.Fatalf("Failed to create ADR directory: %v", err)
+	}
+
+	testFiles := map[string]string{
+		"adr-001-use-go.md":    "# ADR 001: Use Go\n\n**Status**: Accepted  \n\n## Relations\n\n- Related to: [ADR 002](adr-002-use-kafka.md)\n",
+		"adr-002-use-kafka.md": "# ADR 002: Use Kafka\n\n**Status**: Proposed  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(adrDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	outDir := filepath.Join(tempDir, "public")
+	if err := ensureDir(outDir); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+	// Left over from a run when ADR 002 was still public
+	if err := writeFile(filepath.Join(outDir, "adr-002-use-kafka.md"), "stale"); err != nil {
+		t.Fatalf("Failed to create stale file: %v", err)
+	}
+
+	adrs, err := publishADRs(outDir, []string{"Accepted"}, false)
+	if err != nil {
+		t.Fatalf("publishADRs() failed: %v", err)
+	}
+	if len(adrs) != 1 {
+		t.Errorf("publishADRs() published %d ADRs, want 1", len(adrs))
+	}
+
+	content, err := os.ReadFile(filepath.Join(outDir, "adr-001-use-go.md"))
+	if err != nil {
+		t.Fatalf("Accepted ADR was not copied: %v", err)
+	}
+	if !strings.Contains(string(content), "- Related to: ADR 002\n") {
+		t.Errorf("Published ADR = %q, want the link to the unpublished ADR as plain text", content)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "adr-002-use-kafka.md")); !os.IsNotExist(err) {
+		t.Error("Unpublished ADR was left in the output directory")
+	}
+	index, err := os.ReadFile(filepath.Join(outDir, indexFile))
+	if err != nil {
+		t.Fatalf("Index was not written: %v", err)
+	}
+	if !strings.Contains(string(index), "- [Use Go](adr-001-use-go.md)") || strings.Contains(string(index), "Kafka") {
+		t.Errorf("Index = %q, want only the accepted ADR", index)
+	}
+}
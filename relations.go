@@ -0,0 +1,352 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type Relation struct {
+	Type         string
+	TargetNumber string
+}
+
+// ADR numbers are matched as decimal or uppercase Roman numerals, optionally
+// after a category as in "ADR SEC-001"; parseNumber decides whether they are
+// valid in the active --number-format.
+var relationTargetPattern = regexp.MustCompile(`\b(?i:adr)[-_ ]?(?:([A-Za-z][A-Za-z0-9]*)-)?(\d+|[IVXLCDM]+\b)`)
+
+// relationFormat is how relate writes relations: "section" adds
+// "- Type: [ADR N](file)" lines to the Relations section, "frontmatter" adds
+// "Type-name: adr-N" keys to the YAML frontmatter.
+var relationFormat = "section"
+
+var relationFormats = []string{"section", "frontmatter"}
+
+var frontmatterRelationPattern = regexp.MustCompile(`^([A-Za-z][\w-]*):\s*\[?\s*((?i:adr)[-_ ]?(?:\d+|[IVXLCDM]+)[\w.-]*(?:\s*,\s*(?i:adr)[-_ ]?(?:\d+|[IVXLCDM]+)[\w.-]*)*)\s*\]?$`)
+
+func isRelationsHeading(line string) bool {
+	if !strings.HasPrefix(line, "## ") {
+		return false
+	}
+	heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+	return strings.EqualFold(heading, "Relations") || strings.EqualFold(heading, "Links")
+}
+
+func normalizeRelationType(relationType string) string {
+	relationType = strings.TrimSpace(strings.Trim(strings.TrimSpace(relationType), "*_"))
+	if len(relationType) > 4 && strings.EqualFold(relationType[len(relationType)-4:], " ADR") {
+		relationType = relationType[:len(relationType)-4]
+	}
+	return strings.TrimSpace(relationType)
+}
+
+func parseRelationLine(line string) []Relation {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "- ") && !strings.HasPrefix(trimmed, "* ") {
+		return nil
+	}
+
+	// Free-text lines without a "Type: target" shape are ignored
+	relationType, target, ok := strings.Cut(trimmed[2:], ":")
+	if !ok {
+		return nil
+	}
+	relationType = normalizeRelationType(relationType)
+	if relationType == "" {
+		return nil
+	}
+
+	var relations []Relation
+	seen := make(map[string]bool)
+	for _, match := range relationTargetPattern.FindAllStringSubmatch(target, -1) {
+		num, ok := parseNumber(match[2])
+		if !ok {
+			continue
+		}
+		number := formatNumber(num)
+		if match[1] != "" && category == "" {
+			number = qualifiedNumber(match[1], num)
+		}
+		if seen[number] {
+			continue
+		}
+		seen[number] = true
+		relations = append(relations, Relation{Type: relationType, TargetNumber: number})
+	}
+	return relations
+}
+
+// forEachRelationLine calls fn with every line of the Relations section and
+// its 1-based line number. Frontmatter keys whose values are only ADR
+// references ("Superseded-by: adr-012") are relations too, and are passed in
+// the list form ("- Superseded by: adr-012").
+func forEachRelationLine(content string, fn func(lineNumber int, line string)) {
+	lines := strings.Split(content, "\n")
+	bodyStart := 0
+	if end := frontmatterEnd(lines); end > 0 {
+		bodyStart = end + 1
+		for i := 1; i < end; i++ {
+			if match := frontmatterRelationPattern.FindStringSubmatch(strings.TrimSpace(lines[i])); match != nil {
+				fn(i+1, fmt.Sprintf("- %s: %s", strings.ReplaceAll(match[1], "-", " "), match[2]))
+			}
+		}
+	}
+
+	inSection := false
+	for i := bodyStart; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "#") {
+			inSection = isRelationsHeading(trimmed)
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if trimmed == "---" {
+			inSection = false
+			continue
+		}
+		fn(i+1, trimmed)
+	}
+}
+
+// frontmatterEnd returns the index of the line closing the frontmatter, or 0
+// when the content has none.
+func frontmatterEnd(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return i
+		}
+	}
+	return 0
+}
+
+// writeRelation adds relation to content in the --relation-format.
+func writeRelation(content string, relation Relation, targetFilename string) string {
+	if relationFormat == "frontmatter" {
+		return addFrontmatterRelation(content, relation)
+	}
+	return addRelation(content, formatRelation(relation, targetFilename))
+}
+
+// addFrontmatterRelation records relation as a "Type-name: adr-N" key in the
+// frontmatter, adding to the key's list when it is already there and
+// creating the frontmatter when the file has none.
+func addFrontmatterRelation(content string, relation Relation) string {
+	key := strings.ReplaceAll(relation.Type, " ", "-")
+	target := "adr-" + relation.TargetNumber
+	lines := strings.Split(content, "\n")
+
+	end := frontmatterEnd(lines)
+	if end == 0 {
+		return fmt.Sprintf("---\n%s: %s\n---\n\n%s", key, target, content)
+	}
+	for i := 1; i < end; i++ {
+		match := frontmatterRelationPattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if match != nil && strings.EqualFold(match[1], key) {
+			lines[i] = fmt.Sprintf("%s: %s, %s", match[1], match[2], target)
+			return strings.Join(lines, "\n")
+		}
+	}
+	lines = append(lines[:end], append([]string{key + ": " + target}, lines[end:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+func parseRelations(content string) []Relation {
+	var relations []Relation
+	forEachRelationLine(content, func(_ int, line string) {
+		relations = append(relations, parseRelationLine(line)...)
+	})
+	return relations
+}
+
+func formatRelation(relation Relation, targetFilename string) string {
+	if targetFilename == "" {
+		return fmt.Sprintf("- %s: ADR %s", relation.Type, relation.TargetNumber)
+	}
+	return fmt.Sprintf("- %s: [ADR %s](%s)", relation.Type, relation.TargetNumber, targetFilename)
+}
+
+// addRelation appends line to the Relations section, creating the section at
+// the end of the document when it doesn't exist yet.
+func addRelation(content, line string) string {
+	return appendToSection(content, isRelationsHeading, "## Relations", line)
+}
+
+// appendToSection appends line to the first section whose heading matches,
+// or adds the section with the given heading at the end of the document,
+// before its metadata footer.
+func appendToSection(content string, matches func(line string) bool, heading, line string) string {
+	lines := strings.Split(content, "\n")
+
+	sectionStart := -1
+	for i, l := range lines {
+		if matches(strings.TrimSpace(l)) {
+			sectionStart = i
+			break
+		}
+	}
+
+	if sectionStart == -1 {
+		if start, _, ok := footerBlock(lines); ok {
+			before := strings.TrimRight(strings.Join(lines[:start], "\n"), "\n")
+			return before + "\n\n" + heading + "\n\n" + line + "\n\n" + strings.Join(lines[start:], "\n")
+		}
+		content = strings.TrimRight(content, "\n")
+		return content + "\n\n" + heading + "\n\n" + line + "\n"
+	}
+
+	// Insert after the last non-empty line of the section
+	insertAt := sectionStart + 1
+	for i := sectionStart + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			break
+		}
+		if trimmed != "" {
+			insertAt = i + 1
+		}
+	}
+	if insertAt == sectionStart+1 {
+		// Empty section: keep a blank line between the heading and the list
+		lines = append(lines[:insertAt], append([]string{"", line}, lines[insertAt:]...)...)
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines[:insertAt], append([]string{line}, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+var inverseRelationTypes = map[string]string{
+	"replaces":       "Replaced by",
+	"replaced by":    "Replaces",
+	"refines":        "Refined by",
+	"refined by":     "Refines",
+	"depends on":     "Required by",
+	"required by":    "Depends on",
+	"amends":         "Amended by",
+	"amended by":     "Amends",
+	"supersedes":     "Superseded by",
+	"superseded by":  "Supersedes",
+	"related to":     "Related to",
+	"conflicts with": "Conflicts with",
+}
+
+func inverseRelationType(relationType string) string {
+	if inverse, ok := inverseRelationTypes[strings.ToLower(relationType)]; ok {
+		return inverse
+	}
+	return relationType
+}
+
+func hasRelation(content string, relation Relation) bool {
+	for _, existing := range parseRelations(content) {
+		if strings.EqualFold(existing.Type, relation.Type) && existing.TargetNumber == relation.TargetNumber {
+			return true
+		}
+	}
+	return false
+}
+
+// appendRelation adds a relation from one ADR to another. It returns false
+// when the relation was already present.
+func appendRelation(from, to, relationType string) (string, bool, error) {
+	files, err := readDirWithRetry(adrDir)
+	if err != nil {
+		return "", false, err
+	}
+
+	fromFile, ok := findADRFile(files, from)
+	if !ok {
+		return "", false, fmt.Errorf("ADR %s not found in %s", from, adrDir)
+	}
+	toFile, ok := findADRFile(files, to)
+	if !ok {
+		return "", false, fmt.Errorf("ADR %s not found in %s", to, adrDir)
+	}
+
+	path := filepath.Join(adrDir, fromFile)
+	content, err := readFileWithRetry(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	relation := Relation{Type: relationType, TargetNumber: to}
+	if hasRelation(string(content), relation) {
+		return path, false, nil
+	}
+
+	updated := writeRelation(string(content), relation, toFile)
+	if err := withRetry(func() error { return writeFile(path, updated) }); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// validateReference checks the number of an ADR to relate, which outside
+// --category may also name an ADR in a category such as SEC-001. Qualified
+// numbers are normalized to how relations spell them.
+func validateReference(number *string) error {
+	if cat, num, ok := parseQualifiedNumber(*number); ok && category == "" {
+		*number = qualifiedNumber(cat, num)
+		return nil
+	}
+	return validateNumber(*number)
+}
+
+func runRelateCommand(args []string) error {
+	fs := flag.NewFlagSet("relate", flag.ContinueOnError)
+	registerDirFlags(fs)
+	from := fs.String("from", "", "Number of the ADR the relation is added to")
+	to := fs.String("to", "", "Number of the related ADR")
+	relationType := fs.String("type", "Related to", "Relation type (e.g., \"Depends on\", \"Refines\")")
+	bidirectional := fs.Bool("bidirectional", false, "Also add the reciprocal relation to the target ADR")
+	fs.StringVar(&relationFormat, "relation-format", relationFormat, "How relations are written: section (a Relations list) or frontmatter (Type-name: adr-N keys)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+
+	if !slices.Contains(relationFormats, relationFormat) {
+		return fmt.Errorf("invalid --relation-format %q (supported: %s)", relationFormat, strings.Join(relationFormats, ", "))
+	}
+
+	if err := validateReference(from); err != nil {
+		return fmt.Errorf("invalid --from: %v", err)
+	}
+	if err := validateReference(to); err != nil {
+		return fmt.Errorf("invalid --to: %v", err)
+	}
+	if *from == *to {
+		return fmt.Errorf("an ADR cannot be related to itself")
+	}
+	if normalizeRelationType(*relationType) == "" || strings.Contains(*relationType, ":") {
+		return fmt.Errorf("invalid --type %q", *relationType)
+	}
+
+	type link struct{ from, to, relationType string }
+	links := []link{{*from, *to, normalizeRelationType(*relationType)}}
+	if *bidirectional {
+		links = append(links, link{*to, *from, inverseRelationType(normalizeRelationType(*relationType))})
+	}
+
+	for _, l := range links {
+		path, added, err := appendRelation(l.from, l.to, l.relationType)
+		if err != nil {
+			return err
+		}
+		if added {
+			fmt.Printf("✅ Added \"%s: ADR %s\" to %s\n", l.relationType, l.to, path)
+		} else {
+			fmt.Printf("Relation \"%s: ADR %s\" already present in %s\n", l.relationType, l.to, path)
+		}
+	}
+	return nil
+}
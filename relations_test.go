@@ -0,0 +1,216 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRelations(t *testing.T) {
+	content := `# ADR 005: Use Kafka
+
+**Status**: Accepted  
+
+## Relations
+
+- Replaces ADR: 'adr-002-use-rabbitmq.md'
+- Replaced by ADR: 'adr-XXXX.md' _(if applicable)_
+- **Depends on**: [ADR 003](adr-003-event-schema.md), ADR-004
+- Conflicts with: ADR 7
+- Related to: issues, RFCs, previous decisions
+Some free text mentioning adr-009 without a type
+
+---
+
+- Refines: ADR 010
+`
+
+	expected := []Relation{
+		{Type: "Replaces", TargetNumber: "002"},
+		{Type: "Depends on", TargetNumber: "003"},
+		{Type: "Depends on", TargetNumber: "004"},
+		{Type: "Conflicts with", TargetNumber: "007"},
+	}
+
+	result := parseRelations(content)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("parseRelations() = %+v, want %+v", result, expected)
+	}
+}
+
+func TestAddRelationRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "existing section",
+			content:  "# ADR 001: A\n\n## Relations\n\n- Refines: ADR 002\n\n---\n\n_footer_\n",
+			expected: "# ADR 001: A\n\n## Relations\n\n- Refines: ADR 002\n- Depends on: [ADR 004](adr-004-b.md)\n\n---\n\n_footer_\n",
+		},
+		{
+			name:     "missing section",
+			content:  "# ADR 001: A\n\n## Decision\n\nText.\n",
+			expected: "# ADR 001: A\n\n## Decision\n\nText.\n\n## Relations\n\n- Depends on: [ADR 004](adr-004-b.md)\n",
+		},
+		{
+			name:     "empty links section",
+			content:  "# ADR 001: A\n\n## Links\n\n## Notes\n",
+			expected: "# ADR 001: A\n\n## Links\n\n- Depends on: [ADR 004](adr-004-b.md)\n\n## Notes\n",
+		},
+	}
+
+	relation := Relation{Type: "Depends on", TargetNumber: "004"}
+	for _, test := range tests {
+		result := addRelation(test.content, formatRelation(relation, "adr-004-b.md"))
+		if result != test.expected {
+			t.Errorf("%s: addRelation() = %q, want %q", test.name, result, test.expected)
+		}
+
+		found := false
+		for _, parsed := range parseRelations(result) {
+			if parsed == relation {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: written relation %+v was not parsed back from %q", test.name, relation, result)
+		}
+	}
+}
+
+func TestAppendRelation(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"adr-003-use-kafka.md":    "# ADR 003: Use Kafka\n\n**Status**: Accepted  \n\n## Relations\n\n- Related to: issues\n",
+		"adr-008-event-schema.md": "# ADR 008: Event Schema\n\n**Status**: Proposed  \n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		_, added, err := appendRelation("003", "008", "Depends on")
+		if err != nil {
+			t.Fatalf("appendRelation() failed: %v", err)
+		}
+		if added != (i == 0) {
+			t.Errorf("appendRelation() run %d added = %v, want %v", i+1, added, i == 0)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "adr-003-use-kafka.md"))
+	if err != nil {
+		t.Fatalf("Failed to read ADR: %v", err)
+	}
+	line := "- Depends on: [ADR 008](adr-008-event-schema.md)"
+	if strings.Count(string(content), line) != 1 {
+		t.Errorf("ADR content = %q, want exactly one %q", string(content), line)
+	}
+	if !strings.Contains(string(content), "**Status**: Accepted") {
+		t.Error("appendRelation() should not touch the status")
+	}
+
+	if _, _, err := appendRelation("003", "042", "Depends on"); err == nil {
+		t.Error("Expected error when relating to a missing ADR")
+	}
+}
+
+func TestInverseRelationType(t *testing.T) {
+	tests := map[string]string{
+		"Depends on":    "Required by",
+		"Related to":    "Related to",
+		"Replaced by":   "Replaces",
+		"Inspired by X": "Inspired by X",
+	}
+	for input, expected := range tests {
+		if result := inverseRelationType(input); result != expected {
+			t.Errorf("inverseRelationType(%q) = %q, want %q", input, result, expected)
+		}
+	}
+}
+
+func TestAddFrontmatterRelationRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "no frontmatter",
+			content:  "# ADR 001: A\n",
+			expected: "---\nSuperseded-by: adr-012\n---\n\n# ADR 001: A\n",
+		},
+		{
+			name:     "new key",
+			content:  "---\ntags: [db]\n---\n\n# ADR 001: A\n",
+			expected: "---\ntags: [db]\nSuperseded-by: adr-012\n---\n\n# ADR 001: A\n",
+		},
+		{
+			name:     "existing key",
+			content:  "---\nsuperseded-by: adr-011\n---\n\n# ADR 001: A\n",
+			expected: "---\nsuperseded-by: adr-011, adr-012\n---\n\n# ADR 001: A\n",
+		},
+	}
+
+	relation := Relation{Type: "Superseded by", TargetNumber: "012"}
+	for _, test := range tests {
+		result := addFrontmatterRelation(test.content, relation)
+		if result != test.expected {
+			t.Errorf("%s: addFrontmatterRelation() = %q, want %q", test.name, result, test.expected)
+		}
+		if !hasRelation(result, relation) {
+			t.Errorf("%s: written relation %+v was not parsed back from %q", test.name, relation, result)
+		}
+	}
+
+	// Other frontmatter keys are not relations
+	if relations := parseRelations("---\ntitle: Use Go\ndate: 2024-01-01\n---\n"); len(relations) != 0 {
+		t.Errorf("parseRelations() = %+v, want none for plain frontmatter", relations)
+	}
+}
+
+func TestRelateFrontmatterFormat(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalFormat := adrDir, relationFormat
+	adrDir = tempDir
+	defer func() { adrDir, relationFormat = originalAdrDir, originalFormat }()
+
+	for file, content := range map[string]string{
+		"adr-001-a.md": "# ADR 001: A\n",
+		"adr-012-b.md": "# ADR 012: B\n",
+	} {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	if err := runRelateCommand([]string{"--dir", tempDir, "--from", "001", "--to", "012", "--type", "Superseded by", "--bidirectional", "--relation-format", "frontmatter"}); err != nil {
+		t.Fatalf("runRelateCommand() failed: %v", err)
+	}
+	for file, want := range map[string]string{
+		"adr-001-a.md": "---\nSuperseded-by: adr-012\n---\n",
+		"adr-012-b.md": "---\nSupersedes: adr-001\n---\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(tempDir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(content), want) {
+			t.Errorf("%s = %q, want prefix %q", file, content, want)
+		}
+	}
+
+	if err := runRelateCommand([]string{"--dir", tempDir, "--from", "001", "--to", "012", "--relation-format", "prose"}); err == nil {
+		t.Error("runRelateCommand() accepted an unknown --relation-format")
+	}
+}
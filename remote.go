@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// remoteTimeout bounds the fetch of a --template or --index-header URL.
+var remoteTimeout = 10 * time.Second
+
+// remoteFiles holds the files fetched during this run, so a URL is only
+// requested once.
+var remoteFiles = make(map[string]string)
+
+func isRemoteURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// remoteCachePath is where the last fetched copy of url is kept, in the
+// user's cache directory.
+func remoteCachePath(url string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "adrgen", "templates", hex.EncodeToString(sum[:8])+".md"), nil
+}
+
+// fetchRemote downloads url and refreshes its cached copy.
+func fetchRemote(url string) (string, error) {
+	if content, ok := remoteFiles[url]; ok {
+		return content, nil
+	}
+
+	client := &http.Client{Timeout: remoteTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	debugf("fetched %s (%d bytes)", url, len(body))
+
+	content := string(body)
+	remoteFiles[url] = content
+	// The cache lives outside the ADR directory, so it bypasses fsys and the
+	// rollback journal
+	if cachePath, err := remoteCachePath(url); err == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), os.ModePerm); err == nil {
+			if err := os.WriteFile(cachePath, body, 0644); err != nil {
+				debugf("could not cache %s: %v", url, err)
+			}
+		}
+	}
+	return content, nil
+}
+
+// loadRemoteTemplate fetches a --template URL, falling back to the embedded
+// default.
+func loadRemoteTemplate(url string) string {
+	return loadRemote(url, "template", defaultTemplate)
+}
+
+// loadRemote fetches url. When the fetch fails the cached copy from an
+// earlier run is used, or else fallback, with a warning either way. what
+// names the file in the warnings.
+func loadRemote(url, what, fallback string) string {
+	content, err := fetchRemote(url)
+	if err == nil {
+		return content
+	}
+
+	if cachePath, cacheErr := remoteCachePath(url); cacheErr == nil {
+		if cached, cacheErr := os.ReadFile(cachePath); cacheErr == nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch %s %s (%v), using the cached copy\n", what, url, err)
+			remoteFiles[url] = string(cached)
+			return string(cached)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: could not fetch %s %s (%v), using the default %s\n", what, url, err, what)
+	remoteFiles[url] = fallback
+	return fallback
+}
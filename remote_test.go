@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadRemoteTemplate(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	originalFiles, originalFS := remoteFiles, fsys
+	defer func() { remoteFiles, fsys = originalFiles, originalFS }()
+
+	// The cache is written to disk, not through the ADR filesystem
+	mem := newMemFS()
+	fsys = mem
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("# ADR {{number}}: {{title}}\n\nOrg template\n"))
+	}))
+	url := server.URL + "/adr-template.md"
+
+	remoteFiles = make(map[string]string)
+	for i := 0; i < 2; i++ {
+		if got := loadRemoteTemplate(url); !strings.Contains(got, "Org template") {
+			t.Fatalf("loadRemoteTemplate() = %q, want the remote template", got)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Template was fetched %d times, want 1", requests)
+	}
+	cachePath, err := remoteCachePath(url)
+	if err != nil {
+		t.Fatalf("remoteCachePath() failed: %v", err)
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Errorf("Template was not cached on disk: %v", err)
+	}
+	if len(mem.files) != 0 {
+		t.Errorf("Template cache went through fsys: %v", mem.files)
+	}
+
+	// Unreachable: the cached copy from the earlier fetch is used
+	server.Close()
+	remoteFiles = make(map[string]string)
+	var got string
+	_, stderr := captureOutput(t, func() { got = loadRemoteTemplate(url) })
+	if !strings.Contains(got, "Org template") || !strings.Contains(stderr, "cached copy") {
+		t.Errorf("loadRemoteTemplate() = %q (stderr: %q), want the cached template with a warning", got, stderr)
+	}
+
+	// Never fetched and unreachable: the embedded default is used
+	_, stderr = captureOutput(t, func() { got = loadRemoteTemplate(server.URL + "/other.md") })
+	if got != defaultTemplate || !strings.Contains(stderr, "default template") {
+		t.Errorf("loadRemoteTemplate() = %q (stderr: %q), want the default template with a warning", got, stderr)
+	}
+}
+
+func TestLoadRemoteIndexHeader(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	originalFiles, originalHeader, originalAdrDir := remoteFiles, indexHeader, adrDir
+	defer func() { remoteFiles, indexHeader, adrDir = originalFiles, originalHeader, originalAdrDir }()
+	adrDir = t.TempDir()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("# Org Decisions\n"))
+	}))
+	indexHeader = server.URL + "/index-header.md"
+
+	remoteFiles = make(map[string]string)
+	_, rendered, err := renderIndex()
+	if err != nil {
+		t.Fatalf("renderIndex() failed: %v", err)
+	}
+	if !strings.HasPrefix(rendered, "# Org Decisions\n\n") {
+		t.Errorf("renderIndex() = %q, want the remote header", rendered)
+	}
+	if requests != 1 {
+		t.Errorf("Index header was fetched %d times, want 1", requests)
+	}
+
+	// Unreachable: the cached copy from the earlier fetch is used
+	server.Close()
+	remoteFiles = make(map[string]string)
+	_, stderr := captureOutput(t, func() { _, rendered, err = renderIndex() })
+	if err != nil || !strings.HasPrefix(rendered, "# Org Decisions\n\n") || !strings.Contains(stderr, "cached copy") {
+		t.Errorf("renderIndex() = %q, %v (stderr: %q), want the cached header with a warning", rendered, err, stderr)
+	}
+
+	// Never fetched and unreachable: the built-in heading is used
+	indexHeader = server.URL + "/other.md"
+	_, stderr = captureOutput(t, func() { _, rendered, err = renderIndex() })
+	if err != nil || !strings.HasPrefix(rendered, "# 📄 Architecture Decision Records\n\n") || !strings.Contains(stderr, "default index header") {
+		t.Errorf("renderIndex() = %q, %v (stderr: %q), want the built-in heading with a warning", rendered, err, stderr)
+	}
+}
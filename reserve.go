@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// reservedFile lists ADR numbers claimed on branches that haven't been merged
+// yet, one per line with an optional note: "012 feature/caching".
+const reservedFile = ".adr-reserved"
+
+func reservedNumbers() (map[int]bool, error) {
+	content, err := readFileWithRetry(filepath.Join(adrDir, reservedFile))
+	if os.IsNotExist(err) {
+		return map[int]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := make(map[int]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if num, ok := parseNumber(fields[0]); ok {
+			reserved[num] = true
+		}
+	}
+	return reserved, nil
+}
+
+// reserveNumber claims the next free number and records it in the reserved
+// file.
+func reserveNumber(note string) (string, error) {
+	if err := ensureDir(adrDir); err != nil {
+		return "", err
+	}
+	number := getNextADRNumber()
+
+	path := filepath.Join(adrDir, reservedFile)
+	content, err := readFileWithRetry(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	entry := number
+	if note = strings.TrimSpace(note); note != "" {
+		entry += " " + note
+	}
+	updated := string(content)
+	if updated != "" && !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+	updated += entry + "\n"
+
+	if err := withRetry(func() error { return writeFile(path, updated) }); err != nil {
+		return "", err
+	}
+	return number, nil
+}
+
+func runReserveCommand(args []string) error {
+	fs := flag.NewFlagSet("reserve", flag.ContinueOnError)
+	registerDirFlags(fs)
+	note := fs.String("note", "", "Who or which branch the number is reserved for (e.g., feature/caching)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+
+	number, err := reserveNumber(*note)
+	if err != nil {
+		return err
+	}
+	fmt.Println(number)
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReserveNumber(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := writeFile(filepath.Join(tempDir, "adr-001-first.md"), "# ADR 001: First\n"); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := writeFile(filepath.Join(tempDir, reservedFile), "# claimed on branches\n002 feature/cache"); err != nil {
+		t.Fatalf("Failed to create reserved file: %v", err)
+	}
+
+	if result := getNextADRNumber(); result != "003" {
+		t.Errorf("getNextADRNumber() = %q, want %q", result, "003")
+	}
+
+	number, err := reserveNumber("feature/search")
+	if err != nil {
+		t.Fatalf("reserveNumber() failed: %v", err)
+	}
+	if number != "003" {
+		t.Errorf("reserveNumber() = %q, want %q", number, "003")
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, reservedFile))
+	if err != nil {
+		t.Fatalf("Failed to read reserved file: %v", err)
+	}
+	expected := "# claimed on branches\n002 feature/cache\n003 feature/search\n"
+	if string(content) != expected {
+		t.Errorf("Reserved file = %q, want %q", content, expected)
+	}
+	if result := getNextADRNumber(); result != "004" {
+		t.Errorf("getNextADRNumber() = %q, want %q", result, "004")
+	}
+}
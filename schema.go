@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type jsonSchemaProperty struct {
+	Type        string              `json:"type"`
+	Description string              `json:"description,omitempty"`
+	Format      string              `json:"format,omitempty"`
+	Pattern     string              `json:"pattern,omitempty"`
+	Enum        []string            `json:"enum,omitempty"`
+	Items       *jsonSchemaProperty `json:"items,omitempty"`
+}
+
+type jsonSchema struct {
+	Schema               string                        `json:"$schema"`
+	Title                string                        `json:"title"`
+	Type                 string                        `json:"type"`
+	Required             []string                      `json:"required"`
+	Properties           map[string]jsonSchemaProperty `json:"properties"`
+	AdditionalProperties bool                          `json:"additionalProperties"`
+}
+
+func frontmatterSchema() jsonSchema {
+	return jsonSchema{
+		Schema:   "https://json-schema.org/draft/2020-12/schema",
+		Title:    "ADR frontmatter",
+		Type:     "object",
+		Required: []string{"title", "date"},
+		Properties: map[string]jsonSchemaProperty{
+			"number": {Type: "string", Description: "Sequential ADR number", Pattern: `^\d+$`},
+			"title":  {Type: "string", Description: "Descriptive title of the decision"},
+			"status": statusSchema(),
+			"date":   {Type: "string", Description: "Date the decision was recorded", Format: "date"},
+			"tags": {
+				Type:        "array",
+				Description: "Free-form labels for the decision",
+				Items:       &jsonSchemaProperty{Type: "string"},
+			},
+			"deciders": {
+				Type:        "array",
+				Description: "People involved in the decision",
+				Items:       &jsonSchemaProperty{Type: "string"},
+			},
+		},
+		AdditionalProperties: true,
+	}
+}
+
+// statusSchema limits the status to the project's statuses: the known ones
+// plus a configured default status that isn't one of them. A superseded label
+// with the successor's number, such as "Superseded by ADR-{{new}}", can't be
+// listed, so the statuses become a pattern instead.
+func statusSchema() jsonSchemaProperty {
+	property := jsonSchemaProperty{Type: "string", Description: "Current status of the decision"}
+	allowed := slices.Clone(statuses)
+	if _, ok := normalizeStatus(defaultStatus); defaultStatus != "" && !ok {
+		allowed = append(allowed, defaultStatus)
+	}
+	if supersededLabel == "Superseded" {
+		property.Enum = allowed
+		return property
+	}
+
+	alternatives := make([]string, 0, len(allowed)+1)
+	for _, status := range allowed {
+		alternatives = append(alternatives, regexp.QuoteMeta(status))
+	}
+	label := strings.ReplaceAll(regexp.QuoteMeta(supersededLabel), regexp.QuoteMeta("{{new}}"), `\S+`)
+	property.Pattern = "^(" + strings.Join(append(alternatives, label), "|") + ")$"
+	return property
+}
+
+func runSchemaCommand(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	registerDirFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("usage: adrgen schema")
+	}
+	// The status set follows default-status and superseded-label in the
+	// project's config
+	discoverADRDir(fs)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(frontmatterSchema())
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestFrontmatterSchema(t *testing.T) {
+	originalStatuses := statuses
+	statuses = []string{"Draft", "Accepted"}
+	defer func() { statuses = originalStatuses }()
+
+	data, err := json.Marshal(frontmatterSchema())
+	if err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Enum []string `json:"enum"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	if !reflect.DeepEqual(schema.Required, []string{"title", "date"}) {
+		t.Errorf("required = %v, want [title date]", schema.Required)
+	}
+	if !reflect.DeepEqual(schema.Properties["status"].Enum, statuses) {
+		t.Errorf("status enum = %v, want %v", schema.Properties["status"].Enum, statuses)
+	}
+	for _, field := range []string{"tags", "deciders"} {
+		if _, ok := schema.Properties[field]; !ok {
+			t.Errorf("schema is missing the optional %q field", field)
+		}
+	}
+}
+
+func TestSchemaCommandUsesConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalDefault, originalLabel, originalCompanions := adrDir, defaultStatus, supersededLabel, companions
+	defer func() {
+		adrDir, defaultStatus, supersededLabel, companions = originalAdrDir, originalDefault, originalLabel, originalCompanions
+	}()
+	if err := os.Mkdir(filepath.Join(tempDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	schemaStatus := func() (enum []string, pattern string) {
+		t.Helper()
+		var err error
+		stdout, _ := captureOutput(t, func() { err = runSchemaCommand([]string{"--dir", tempDir}) })
+		if err != nil {
+			t.Fatalf("runSchemaCommand() failed: %v", err)
+		}
+		var schema struct {
+			Properties map[string]struct {
+				Enum    []string `json:"enum"`
+				Pattern string   `json:"pattern"`
+			} `json:"properties"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &schema); err != nil {
+			t.Fatalf("Schema is not valid JSON: %v", err)
+		}
+		return schema.Properties["status"].Enum, schema.Properties["status"].Pattern
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, configFile), []byte("default-status: Draft\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	enum, _ := schemaStatus()
+	if want := append(append([]string{}, statuses...), "Draft"); !reflect.DeepEqual(enum, want) {
+		t.Errorf("status enum = %v, want %v", enum, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, configFile), []byte("default-status: Draft\nsuperseded-label: \"Superseded by ADR-{{new}}\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	enum, pattern := schemaStatus()
+	if enum != nil || pattern == "" {
+		t.Fatalf("status enum = %v, pattern = %q, want only a pattern", enum, pattern)
+	}
+	re := regexp.MustCompile(pattern)
+	for status, valid := range map[string]bool{"Accepted": true, "Draft": true, "Superseded by ADR-012": true, "Superseded": true, "Parked": false} {
+		if re.MatchString(status) != valid {
+			t.Errorf("pattern %q matches %q = %v, want %v", pattern, status, !valid, valid)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// fuzzyMatch reports whether the characters of query appear in text in
+// order, ignoring case and spaces in the query.
+func fuzzyMatch(query, text string) bool {
+	text = strings.ToLower(text)
+	for _, r := range strings.ToLower(strings.ReplaceAll(query, " ", "")) {
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+	return true
+}
+
+// adrChoices returns the numbered ADRs and their select labels.
+func adrChoices(adrs []ADR) ([]string, []string) {
+	var numbers, labels []string
+	for _, adr := range adrs {
+		if adr.Number == "" {
+			continue
+		}
+		label := fmt.Sprintf("%s  %s", adr.Number, adr.Title)
+		if adr.Status != "" {
+			label += fmt.Sprintf("  (%s)", adr.Status)
+		}
+		numbers = append(numbers, adr.Number)
+		labels = append(labels, label)
+	}
+	return numbers, labels
+}
+
+// promptForExistingADR lets the user pick the ADR to update from a
+// searchable list and returns its number.
+func promptForExistingADR() (string, error) {
+	adrs, err := loadADRs()
+	if err != nil {
+		return "", err
+	}
+	numbers, labels := adrChoices(adrs)
+	if len(numbers) == 0 {
+		return "", fmt.Errorf("no ADRs to update in %s", adrDir)
+	}
+
+	prompt := promptui.Select{
+		Label:             "Select ADR (type to search)",
+		Items:             labels,
+		Size:              10,
+		StartInSearchMode: true,
+		Templates:         selectTemplates(),
+		Searcher: func(input string, index int) bool {
+			return fuzzyMatch(input, labels[index])
+		},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+	return numbers[index], nil
+}
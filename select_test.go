@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		expected    bool
+	}{
+		{"", "007  Use Redis  (Accepted)", true},
+		{"redis", "007  Use Redis  (Accepted)", true},
+		{"7 rds", "007  Use Redis  (Accepted)", true},
+		{"acc", "007  Use Redis  (Accepted)", true},
+		{"kafka", "007  Use Redis  (Accepted)", false},
+		{"sider", "007  Use Redis  (Accepted)", false},
+	}
+	for _, test := range tests {
+		if result := fuzzyMatch(test.query, test.text); result != test.expected {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", test.query, test.text, result, test.expected)
+		}
+	}
+}
+
+func TestADRChoices(t *testing.T) {
+	adrs := []ADR{
+		{Number: "001", Title: "Use Go", Status: "Accepted"},
+		{Title: "Notes"},
+		{Number: "002", Title: "Use Kafka"},
+	}
+	numbers, labels := adrChoices(adrs)
+	if len(numbers) != 2 || numbers[0] != "001" || numbers[1] != "002" {
+		t.Errorf("adrChoices() numbers = %v, want [001 002]", numbers)
+	}
+	if len(labels) != 2 || labels[0] != "001  Use Go  (Accepted)" || labels[1] != "002  Use Kafka" {
+		t.Errorf("adrChoices() labels = %q", labels)
+	}
+}
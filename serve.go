@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const servePage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>body { max-width: 50em; margin: 2em auto; padding: 0 1em; font-family: sans-serif; line-height: 1.5; } pre { background: #f4f4f4; padding: 1em; overflow-x: auto; }</style>
+</head>
+<body>
+<p><a href="/">Index</a></p>
+%s</body>
+</html>
+`
+
+func writePage(w http.ResponseWriter, title, markdown string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, servePage, html.EscapeString(title), markdownToHTML(markdown))
+}
+
+// serveHandler serves the ADR directory read-only: the index (rendered fresh
+// on every request) at /, ADRs as HTML, other files as they are, and a
+// /healthz check. Hidden files such as the rollback journal are not served.
+func serveHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "read-only server", http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" || name == indexFile {
+			_, index, err := renderIndex()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writePage(w, "Architecture Decision Records", index)
+			return
+		}
+		for _, part := range strings.Split(name, "/") {
+			if strings.HasPrefix(part, ".") {
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		content, err := fsys.ReadFile(filepath.Join(adrDir, filepath.FromSlash(name)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if !strings.HasSuffix(name, ".md") {
+			http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+			return
+		}
+		title := getCurrentTitle(string(content))
+		if title == "" {
+			title = name
+		}
+		writePage(w, title, string(content))
+	})
+	return mux
+}
+
+func runServeCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	registerDirFlags(fs)
+	registerIndexFlags(fs)
+	port := fs.Int("port", 8080, "Port to listen on (localhost only)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+
+	// The landing page is always the Markdown index rendered as HTML
+	indexFormat, indexFragment = "markdown", false
+	if _, _, err := renderIndex(); err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("localhost:%d", *port)
+	fmt.Printf("Serving %s at http://%s/ (press Ctrl+C to stop)\n", adrDir, addr)
+	return http.ListenAndServe(addr, serveHandler())
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeHandler(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	testFiles := map[string]string{
+		"adr-001-use-go.md":    "# ADR 001: Use Go\n\n## Relations\n\n- Related to: [ADR 002](adr-002-use-json.md)\n",
+		"adr-002-use-json.md":  "# ADR 002: Use JSON\n",
+		"diagram.svg":          "<svg></svg>",
+		".adrgen/last-op.json": "{}",
+	}
+	for file, content := range testFiles {
+		if err := fsys.MkdirAll(filepath.Dir(filepath.Join(tempDir, file))); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	server := httptest.NewServer(serveHandler())
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{"/", http.StatusOK, `<a href="adr-001-use-go.md">Use Go</a>`},
+		{"/adr-001-use-go.md", http.StatusOK, `<a href="adr-002-use-json.md">ADR 002</a>`},
+		{"/diagram.svg", http.StatusOK, "<svg></svg>"},
+		{"/healthz", http.StatusOK, "ok"},
+		{"/.adrgen/last-op.json", http.StatusNotFound, ""},
+		{"/adr-009-missing.md", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(server.URL + tt.path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.want) {
+			t.Errorf("GET %s = %d %q, want %d containing %q", tt.path, resp.StatusCode, body, tt.status, tt.want)
+		}
+	}
+
+	resp, err := http.Post(server.URL+"/adr-001-use-go.md", "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// singleFile is the file the single-file export writes all ADRs to.
+const singleFile = "DECISIONS.md"
+
+var (
+	markdownHeadingLine = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	markdownFileLink    = regexp.MustCompile(`\]\(([^)\s#]+\.md)\)`)
+)
+
+// markdownAnchors hands out the anchors GitHub generates for headings:
+// lowercased, punctuation dropped, spaces turned into dashes, and repeats
+// numbered -1, -2, ... in document order.
+type markdownAnchors map[string]int
+
+func (seen markdownAnchors) next(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	anchor := b.String()
+	if n := seen[anchor]; n > 0 {
+		seen[anchor] = n + 1
+		return fmt.Sprintf("%s-%d", anchor, n)
+	}
+	seen[anchor] = 1
+	return anchor
+}
+
+// scanHeadings assigns anchors to the headings of content, skipping fenced
+// code, and returns the anchor of its "# ADR" heading.
+func (seen markdownAnchors) scanHeadings(content string) string {
+	adrAnchor := ""
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		match := markdownHeadingLine.FindStringSubmatch(line)
+		if inFence || match == nil {
+			continue
+		}
+		anchor := seen.next(match[1])
+		if adrAnchor == "" && strings.HasPrefix(line, "# ADR") {
+			adrAnchor = anchor
+		}
+	}
+	return adrAnchor
+}
+
+// exportSingleFile concatenates the ADRs in number order into DECISIONS.md,
+// separated by rules, after a table of contents. Links between ADRs point to
+// their sections in the file.
+func exportSingleFile(adrs []ADR, outDir string) error {
+	if same, _ := sameDir(outDir, adrDir); same {
+		return fmt.Errorf("refusing to export %s into the source directory %s", singleFile, adrDir)
+	}
+	if err := ensureDir(outDir); err != nil {
+		return err
+	}
+
+	var numbered []ADR
+	for _, adr := range adrs {
+		if adr.Number == "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s has no ADR number and was left out\n", adr.Filename)
+			continue
+		}
+		numbered = append(numbered, adr)
+	}
+	sort.SliceStable(numbered, func(i, j int) bool {
+		a, _ := parseNumber(numbered[i].Number)
+		b, _ := parseNumber(numbered[j].Number)
+		return a < b
+	})
+
+	const title = "Architecture Decision Records"
+	seen := markdownAnchors{}
+	seen.next(title)
+	seen.next("Contents")
+
+	sections := make([]string, len(numbered))
+	anchors := make(map[string]string)
+	var toc []string
+	for i, adr := range numbered {
+		content, err := readFileWithRetry(filepath.Join(adrDir, adr.Filename))
+		if err != nil {
+			return err
+		}
+		section := strings.TrimSpace(string(content))
+		if getHeadingLine(section) == "" {
+			section = updateTitle(section, adr.Number, adr.Title)
+		}
+		sections[i] = section
+		anchors[adr.Filename] = seen.scanHeadings(section)
+		toc = append(toc, fmt.Sprintf("- [%s](#%s)", strings.TrimPrefix(getHeadingLine(section), "# "), anchors[adr.Filename]))
+	}
+
+	for i, section := range sections {
+		sections[i] = markdownFileLink.ReplaceAllStringFunc(section, func(match string) string {
+			target := markdownFileLink.FindStringSubmatch(match)[1]
+			if anchor, ok := anchors[path.Base(target)]; ok {
+				return "](#" + anchor + ")"
+			}
+			return match
+		})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n## Contents\n\n", title)
+	if len(toc) > 0 {
+		b.WriteString(strings.Join(toc, "\n") + "\n")
+	}
+	for _, section := range sections {
+		b.WriteString("\n---\n\n" + section + "\n")
+	}
+	return writeFile(filepath.Join(outDir, singleFile), b.String())
+}
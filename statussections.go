@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// indexStatusSections is the --status-sections list, e.g.
+// "Accepted,Proposed,Superseded=collapsed,Rejected=hidden": the order of the
+// status groups in the index and how each is shown. Statuses it leaves out
+// follow in the usual order.
+var indexStatusSections = ""
+
+const (
+	sectionShown     = "shown"
+	sectionCollapsed = "collapsed"
+	sectionHidden    = "hidden"
+)
+
+type statusSection struct {
+	Status string
+	Mode   string
+}
+
+// parseStatusSections parses "Status[=mode]" entries; a "Status: mode" form
+// is accepted too, as written in .adrgen.yaml lists.
+func parseStatusSections(spec string) ([]statusSection, error) {
+	var sections []statusSection
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, mode, ok := strings.Cut(entry, "=")
+		if !ok {
+			name, mode, _ = strings.Cut(entry, ":")
+		}
+		mode = strings.ToLower(strings.TrimSpace(mode))
+		if mode == "" {
+			mode = sectionShown
+		}
+		if mode != sectionShown && mode != sectionCollapsed && mode != sectionHidden {
+			return nil, fmt.Errorf("invalid --status-sections entry %q (modes: shown, collapsed, hidden)", strings.TrimSpace(entry))
+		}
+		status, known := normalizeStatus(strings.TrimSpace(name))
+		if !known {
+			if !strings.EqualFold(strings.TrimSpace(name), "Other") {
+				return nil, fmt.Errorf("invalid --status-sections entry %q (statuses: %s, Other)", strings.TrimSpace(entry), strings.Join(statuses, ", "))
+			}
+			status = "Other"
+		}
+		if seen[status] {
+			return nil, fmt.Errorf("status %s is listed twice in --status-sections", status)
+		}
+		seen[status] = true
+		sections = append(sections, statusSection{Status: status, Mode: mode})
+	}
+	return sections, nil
+}
+
+// statusGroups orders the status groups by --status-sections, dropping the
+// hidden ones and marking the collapsed ones.
+func statusGroups(byStatus map[string][]ADR) []adrGroup {
+	sections, _ := parseStatusSections(indexStatusSections)
+	listed := make(map[string]bool)
+	for _, section := range sections {
+		listed[section.Status] = true
+	}
+	for _, status := range append(append([]string{}, statuses...), "Other") {
+		if !listed[status] {
+			sections = append(sections, statusSection{Status: status, Mode: sectionShown})
+		}
+	}
+
+	var groups []adrGroup
+	for _, section := range sections {
+		if section.Mode == sectionHidden || len(byStatus[section.Status]) == 0 {
+			continue
+		}
+		groups = append(groups, adrGroup{Name: section.Status, ADRs: byStatus[section.Status], Collapsed: section.Mode == sectionCollapsed})
+	}
+	return groups
+}
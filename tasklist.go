@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// taskListDone lists the statuses the tasklist index checks off; every other
+// status, Proposed included, is left open.
+var taskListDone = "Accepted,Rejected,Superseded"
+
+// taskListIndexFormatter renders the index as a GitHub task list, so decisions
+// still waiting for acceptance stand out as unchecked boxes.
+type taskListIndexFormatter struct{}
+
+func (taskListIndexFormatter) Extension() string {
+	return ".md"
+}
+
+func (taskListIndexFormatter) Format(adrs []ADR) string {
+	done := make(map[string]bool)
+	for _, status := range strings.Split(taskListDone, ",") {
+		done[strings.ToLower(strings.TrimSpace(status))] = true
+	}
+
+	var b strings.Builder
+	writeIndexHeading(&b, "# ", "📄 Architecture Decision Records")
+	if len(adrs) == 0 {
+		if indexEmptyMessage != "" {
+			b.WriteString(indexEmptyMessage + "\n")
+		}
+		return b.String()
+	}
+	for i, group := range indexGroups(adrs) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeMarkdownGroupStart(&b, group)
+		for _, adr := range group.ADRs {
+			box := "[ ]"
+			if done[strings.ToLower(adr.Status)] {
+				box = "[x]"
+			}
+			fmt.Fprintf(&b, "- %s [%s](%s)", box, adr.Title, adr.Filename)
+			if adr.Status != "" {
+				b.WriteString(" (" + adr.Status + ")")
+			}
+			if indexWithSummary && adr.Summary != "" {
+				b.WriteString(" — " + adr.Summary)
+			}
+			b.WriteString("\n")
+		}
+		writeMarkdownGroupEnd(&b, group)
+	}
+	return b.String()
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/manifoldco/promptui"
+)
+
+type templateInfo struct {
+	Name    string
+	File    string
+	Summary string
+}
+
+// templatePath is the file of the named template: template-<name>.md, or
+// template_<name>.md when only that one exists.
+func templatePath(name string) string {
+	if name == "" || name == "default" {
+		return filepath.Join(adrDir, templateFile)
+	}
+	path := filepath.Join(adrDir, fmt.Sprintf("template-%s.md", name))
+	if _, err := fsys.Stat(path); err != nil {
+		alternative := filepath.Join(adrDir, fmt.Sprintf("template_%s.md", name))
+		if _, err := fsys.Stat(alternative); err == nil {
+			return alternative
+		}
+	}
+	return path
+}
+
+func templateNameFromFile(filename string) string {
+	name := strings.TrimSuffix(strings.TrimPrefix(filename, "template"), ".md")
+	if name == "" {
+		return "default"
+	}
+	return strings.TrimLeft(name, "-_")
+}
+
+func templateSummary(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+	}
+	return ""
+}
+
+func listTemplates() ([]templateInfo, error) {
+	files, err := fsys.ReadDir(adrDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var templates []templateInfo
+	hasDefault := false
+	for _, file := range files {
+		if file.IsDir() || !isTemplateFile(file.Name()) {
+			continue
+		}
+		// Only list names --template resolves to this file, leaving out
+		// e.g. template_x.md next to template-x.md
+		name := templateNameFromFile(file.Name())
+		if name == "" || templatePath(name) != filepath.Join(adrDir, file.Name()) {
+			continue
+		}
+
+		content, err := fsys.ReadFile(filepath.Join(adrDir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		info := templateInfo{
+			Name:    name,
+			File:    file.Name(),
+			Summary: templateSummary(string(content)),
+		}
+		if info.Name == "default" {
+			hasDefault = true
+		}
+		templates = append(templates, info)
+	}
+
+	// Without a template.md the embedded template is what gets used
+	if !hasDefault {
+		templates = append(templates, templateInfo{
+			Name:    "default",
+			File:    "(embedded)",
+			Summary: templateSummary(loadTemplateOrDefault()),
+		})
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		if (templates[i].Name == "default") != (templates[j].Name == "default") {
+			return templates[i].Name == "default"
+		}
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// initTemplate writes the embedded default template to template.md so it can
+// be customized. An existing template is only replaced when force is set.
+func initTemplate(force bool) (string, error) {
+	path := templatePath("")
+	if _, err := fsys.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err := ensureDir(adrDir); err != nil {
+		return "", err
+	}
+	if err := withRetry(func() error { return writeFile(path, defaultTemplate) }); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func runTemplateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: adrgen template list|init")
+	}
+
+	switch args[0] {
+	case "list":
+		discoverADRDir(nil)
+		templates, err := listTemplates()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, t := range templates {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name, t.File, t.Summary)
+		}
+		return w.Flush()
+	case "init":
+		fs := flag.NewFlagSet("template init", flag.ContinueOnError)
+		registerDirFlags(fs)
+		force := fs.Bool("force", false, "Overwrite an existing template.md")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		discoverADRDir(fs)
+
+		path, err := initTemplate(*force)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Default template written to %s\n", path)
+		return nil
+	default:
+		return fmt.Errorf("unknown template command %q (usage: adrgen template list|init)", args[0])
+	}
+}
+
+// builtinPlaceholders are the placeholders adrgen fills itself. The first
+// four are always set and can't be overridden with --template-var.
+var builtinPlaceholders = []string{"number", "title", "status", "date", "category", "author", "commit", "tags", "timestamp"}
+
+func isReservedPlaceholder(key string) bool {
+	return slices.Contains(builtinPlaceholders[:4], key)
+}
+
+// templatePlaceholders lists the keys of the {{key}} placeholders template
+// declares, each once, in the order they first appear. Go templates have
+// none.
+func templatePlaceholders(template string) []string {
+	if usesGoTemplate(template) {
+		return nil
+	}
+	var keys []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		if !slices.Contains(keys, match[1]) {
+			keys = append(keys, match[1])
+		}
+	}
+	return keys
+}
+
+// customPlaceholders are the placeholders of template that adrgen has no
+// value for, so they need a --template-var.
+func customPlaceholders(template string) []string {
+	var custom []string
+	for _, key := range templatePlaceholders(template) {
+		if !slices.Contains(builtinPlaceholders, key) {
+			custom = append(custom, key)
+		}
+	}
+	return custom
+}
+
+// missingPlaceholders lists the placeholders of template without a value in
+// vars, as "{{key}}".
+func missingPlaceholders(template string, vars map[string]string) []string {
+	var missing []string
+	for _, key := range templatePlaceholders(template) {
+		if _, ok := vars[key]; !ok {
+			missing = append(missing, "{{"+key+"}}")
+		}
+	}
+	return missing
+}
+
+// templateDefaults are the answers preselected when prompting for custom
+// placeholders.
+var templateDefaults map[string]string
+
+// configuredTemplateDefaults reads the template-defaults list of the config
+// file, given as "key=value" or "key: value" items.
+func configuredTemplateDefaults(content string) map[string]string {
+	defaults := make(map[string]string)
+	for _, item := range configList(content, "template-defaults") {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			key, value, ok = strings.Cut(item, ":")
+		}
+		key = strings.TrimSpace(key)
+		if !ok || !placeholderKeyPattern.MatchString(key) {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring template default %q in %s\n", item, configFile)
+			continue
+		}
+		defaults[key] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return defaults
+}
+
+// promptForPlaceholders asks for each custom placeholder of template that
+// vars has no value for, in the order they appear. Empty answers leave the
+// placeholder unset.
+func promptForPlaceholders(template string, vars templateVars) (templateVars, error) {
+	for _, key := range customPlaceholders(template) {
+		if _, ok := vars[key]; ok {
+			continue
+		}
+		prompt := promptui.Prompt{
+			Label:     key,
+			Default:   templateDefaults[key],
+			AllowEdit: true,
+			Templates: promptTemplates(),
+		}
+		value, err := prompt.Run()
+		if err != nil {
+			return vars, err
+		}
+		if value != "" {
+			if vars == nil {
+				vars = make(templateVars)
+			}
+			vars[key] = value
+		}
+	}
+	return vars, nil
+}
+
+func formatPlaceholders(keys []string) string {
+	if len(keys) == 0 {
+		return "none"
+	}
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = "{{" + key + "}}"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sampleTemplateVars are the values validate-template renders with.
+var sampleTemplateVars = map[string]string{
+	"number":    "042",
+	"title":     "Sample Decision",
+	"status":    "Proposed",
+	"date":      "2024-01-31",
+	"author":    "Jane Doe <jane@example.com>",
+	"commit":    "0a1b2c3",
+	"category":  "SEC",
+	"tags":      "sample, template",
+	"timestamp": "2024-01-31T14:30:00-03:00",
+}
+
+// renderSampleTemplate expands the includes of template and renders it with
+// the sample values plus extra, returning the placeholders left unresolved.
+func renderSampleTemplate(template string, extra templateVars) (string, []string, error) {
+	expanded, err := expandIncludes(template)
+	if err != nil {
+		return "", nil, err
+	}
+	vars := make(map[string]string, len(sampleTemplateVars)+len(extra))
+	for key, value := range sampleTemplateVars {
+		vars[key] = value
+	}
+	for key, value := range extra {
+		vars[key] = value
+	}
+	rendered, err := renderADRTemplate(expanded, vars)
+	if err != nil || usesGoTemplate(expanded) {
+		return rendered, nil, err
+	}
+	return rendered, unresolvedPlaceholders(rendered), nil
+}
+
+func runValidateTemplateCommand(args []string) error {
+	fs := flag.NewFlagSet("validate-template", flag.ContinueOnError)
+	registerDirFlags(fs)
+	strict := fs.Bool("strict", false, "Exit non-zero when placeholders are left unresolved")
+	var extra templateVars
+	fs.Var(&extra, "template-var", "Sample value for a custom placeholder as key=value (repeatable)")
+	fs.StringVar(&templateEngine, "template-engine", templateEngine, "How the template is rendered: simple or gotemplate (detected when not set)")
+	path, flagArgs := "", args
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		path, flagArgs = args[0], args[1:]
+	}
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	discoverADRDir(fs)
+	if templateEngine != "" && !slices.Contains(templateEngines, templateEngine) {
+		return fmt.Errorf("invalid --template-engine %q (supported: %s)", templateEngine, strings.Join(templateEngines, ", "))
+	}
+	if path == "" && fs.NArg() == 1 {
+		path = fs.Arg(0)
+	} else if fs.NArg() > 0 {
+		return fmt.Errorf("usage: adrgen validate-template [file]")
+	}
+	if path == "" && isRemoteURL(templateName) {
+		path = templateName
+	} else if path == "" {
+		path = templatePath(templateName)
+	}
+
+	var template string
+	var err error
+	if isRemoteURL(path) {
+		template, err = fetchRemote(path)
+	} else {
+		var content []byte
+		content, err = readFileWithRetry(path)
+		template = string(content)
+	}
+	if err != nil {
+		return err
+	}
+	rendered, missing, err := renderSampleTemplate(template, extra)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	fmt.Print(rendered)
+	if expanded, err := expandIncludes(template); err == nil {
+		fmt.Fprintf(os.Stderr, "Placeholders: %s\n", formatPlaceholders(templatePlaceholders(expanded)))
+		fmt.Fprintf(os.Stderr, "Required (set with --template-var): %s\n", formatPlaceholders(customPlaceholders(expanded)))
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	if *strict {
+		return fmt.Errorf("%s: unresolved placeholder(s) %s", path, strings.Join(missing, ", "))
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s: unresolved placeholder(s) %s\n", path, strings.Join(missing, ", "))
+	return nil
+}
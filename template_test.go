@@ -0,0 +1,238 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateNameFromFile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"template.md", "default"},
+		{"template-lightweight.md", "lightweight"},
+		{"template_security.md", "security"},
+	}
+
+	for _, test := range tests {
+		result := templateNameFromFile(test.input)
+		if result != test.expected {
+			t.Errorf("templateNameFromFile(%q) = %q, want %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	// Without any template files only the embedded default is listed
+	templates, err := listTemplates()
+	if err != nil {
+		t.Fatalf("listTemplates() failed: %v", err)
+	}
+	if len(templates) != 1 || templates[0].Name != "default" || templates[0].File != "(embedded)" {
+		t.Errorf("listTemplates() = %+v, want only the embedded default", templates)
+	}
+
+	testFiles := map[string]string{
+		"template.md":               "# ADR {{number}}: {{title}}\n",
+		"template-lightweight.md":   "Intro\n\n## Lightweight decision\n",
+		"adr-001-not-a-template.md": "# ADR 001: Not A Template\n",
+	}
+	for file, content := range testFiles {
+		if err := writeFile(filepath.Join(tempDir, file), content); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	templates, err = listTemplates()
+	if err != nil {
+		t.Fatalf("listTemplates() failed: %v", err)
+	}
+
+	expected := []templateInfo{
+		{Name: "default", File: "template.md", Summary: "ADR {{number}}: {{title}}"},
+		{Name: "lightweight", File: "template-lightweight.md", Summary: "Lightweight decision"},
+	}
+	if len(templates) != len(expected) {
+		t.Fatalf("listTemplates() = %+v, want %+v", templates, expected)
+	}
+	for i := range expected {
+		if templates[i] != expected[i] {
+			t.Errorf("listTemplates()[%d] = %+v, want %+v", i, templates[i], expected[i])
+		}
+	}
+}
+
+func TestLoadNamedTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir, originalTemplateName := adrDir, templateName
+	adrDir, templateName = tempDir, "lightweight"
+	defer func() { adrDir, templateName = originalAdrDir, originalTemplateName }()
+
+	customTemplate := "Lightweight {{number}} {{title}}"
+	if err := writeFile(filepath.Join(tempDir, "template-lightweight.md"), customTemplate); err != nil {
+		t.Fatalf("Failed to create test template file: %v", err)
+	}
+
+	if result := loadTemplateOrDefault(); result != customTemplate {
+		t.Errorf("loadTemplateOrDefault() = %q, want %q", result, customTemplate)
+	}
+}
+
+func TestInitTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = filepath.Join(tempDir, "docs", "adr")
+	defer func() { adrDir = originalAdrDir }()
+
+	path, err := initTemplate(false)
+	if err != nil {
+		t.Fatalf("initTemplate() failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if string(content) != defaultTemplate {
+		t.Error("initTemplate() did not write the embedded default template")
+	}
+
+	// An existing template is kept unless forced
+	if err := writeFile(path, "# Custom\n"); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if _, err := initTemplate(false); err == nil {
+		t.Error("Expected an error when template.md already exists")
+	}
+	if content, _ := os.ReadFile(path); string(content) != "# Custom\n" {
+		t.Errorf("Existing template was overwritten: %q", content)
+	}
+
+	if _, err := initTemplate(true); err != nil {
+		t.Fatalf("initTemplate(force) failed: %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != defaultTemplate {
+		t.Error("initTemplate(force) did not overwrite the existing template")
+	}
+}
+
+func TestListedTemplatesResolve(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	for _, file := range []string{"template.md", "template-lightweight.md", "template_security.md", "template-x.md", "template_x.md", "templatey.md"} {
+		if err := writeFile(filepath.Join(tempDir, file), "# "+file+"\n"); err != nil {
+			t.Fatalf("Failed to create test file %q: %v", file, err)
+		}
+	}
+
+	templates, err := listTemplates()
+	if err != nil {
+		t.Fatalf("listTemplates() failed: %v", err)
+	}
+	var names []string
+	for _, template := range templates {
+		names = append(names, template.Name)
+		if path := templatePath(template.Name); path != filepath.Join(tempDir, template.File) {
+			t.Errorf("templatePath(%q) = %q, want the listed %s", template.Name, path, template.File)
+		}
+	}
+	if got, want := strings.Join(names, ","), "default,lightweight,security,x"; got != want {
+		t.Errorf("listTemplates() names = %s, want %s", got, want)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	if err := ensureDir(filepath.Join(tempDir, includeDir)); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := writeFile(filepath.Join(tempDir, includeDir, "footer.md"), "Owner: {{owner}}\n"); err != nil {
+		t.Fatalf("Failed to create include: %v", err)
+	}
+	path := filepath.Join(tempDir, "template-team.md")
+	if err := writeFile(path, "# ADR {{number}}: {{title}}\n\n{{include \"footer.md\"}}\nTicket: {{ticket}}\n"); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	var err error
+	stdout, stderr := captureOutput(t, func() { err = runValidateTemplateCommand([]string{path}) })
+	if err != nil {
+		t.Errorf("runValidateTemplateCommand() failed without --strict: %v", err)
+	}
+	if stdout != "# ADR 042: Sample Decision\n\nOwner: {{owner}}\nTicket: {{ticket}}\n" {
+		t.Errorf("stdout = %q, want the rendered template", stdout)
+	}
+	if !strings.Contains(stderr, "{{owner}}, {{ticket}}") {
+		t.Errorf("stderr = %q, want the unresolved placeholders", stderr)
+	}
+	if !strings.Contains(stderr, "Required (set with --template-var): {{owner}}, {{ticket}}") {
+		t.Errorf("stderr = %q, want the custom placeholders reported", stderr)
+	}
+
+	captureOutput(t, func() { err = runValidateTemplateCommand([]string{path, "--strict"}) })
+	if err == nil {
+		t.Error("runValidateTemplateCommand(--strict) should fail with unresolved placeholders")
+	}
+
+	captureOutput(t, func() {
+		err = runValidateTemplateCommand([]string{path, "--strict", "--template-var", "owner=alice", "--template-var", "ticket=ARCH-1"})
+	})
+	if err != nil {
+		t.Errorf("runValidateTemplateCommand() failed with all placeholders set: %v", err)
+	}
+}
+
+func TestValidateTemplateBuiltins(t *testing.T) {
+	tempDir := t.TempDir()
+	originalAdrDir := adrDir
+	adrDir = tempDir
+	defer func() { adrDir = originalAdrDir }()
+
+	for _, key := range builtinPlaceholders {
+		if _, ok := sampleTemplateVars[key]; !ok {
+			t.Errorf("sampleTemplateVars has no value for the builtin {{%s}}", key)
+		}
+	}
+
+	path := filepath.Join(tempDir, "template-timed.md")
+	if err := writeFile(path, "# ADR {{number}}: {{title}}\n\n**Timestamp**: {{timestamp}}\n"); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+	var err error
+	stdout, _ := captureOutput(t, func() { err = runValidateTemplateCommand([]string{path, "--strict"}) })
+	if err != nil {
+		t.Errorf("runValidateTemplateCommand(--strict) failed on {{timestamp}}: %v", err)
+	}
+	if !strings.Contains(stdout, "**Timestamp**: 2024-01-31T14:30:00-03:00") {
+		t.Errorf("stdout = %q, want the sample timestamp", stdout)
+	}
+}
+
+func TestTemplatePlaceholders(t *testing.T) {
+	template := "# ADR {{number}}: {{title}}\n\nTicket: {{ticket}}\nOwner: {{author}} ({{team.name}})\n\nSee {{ticket}}.\n"
+
+	if got, want := strings.Join(templatePlaceholders(template), ","), "number,title,ticket,author,team.name"; got != want {
+		t.Errorf("templatePlaceholders() = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(customPlaceholders(template), ","), "ticket,team.name"; got != want {
+		t.Errorf("customPlaceholders() = %s, want %s", got, want)
+	}
+	missing := missingPlaceholders(template, map[string]string{"number": "001", "title": "Use Go", "ticket": "ARCH-1"})
+	if got, want := strings.Join(missing, ","), "{{author}},{{team.name}}"; got != want {
+		t.Errorf("missingPlaceholders() = %s, want %s", got, want)
+	}
+}
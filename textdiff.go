@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells bounds the LCS table; larger changes are shown as one block.
+const maxDiffCells = 4_000_000
+
+// unifiedDiff returns a unified diff of two texts without context lines, or
+// "" when they are equal.
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	a, b := splitLines(oldText), splitLines(newText)
+
+	// Only the differing middle needs the LCS table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	writeHunk := func(aStart int, removed []string, bStart int, added []string) {
+		if len(removed) == 0 && len(added) == 0 {
+			return
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aStart, len(removed)), hunkRange(bStart, len(added)))
+		for _, line := range removed {
+			out.WriteString("-" + line + "\n")
+		}
+		for _, line := range added {
+			out.WriteString("+" + line + "\n")
+		}
+	}
+
+	if len(midA)*len(midB) > maxDiffCells {
+		writeHunk(prefix, midA, prefix, midB)
+		return out.String()
+	}
+
+	// lcs[i][j] is the common subsequence length of midA[i:] and midB[j:]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		startA, startB := i, j
+		for (i < len(midA) || j < len(midB)) && !(i < len(midA) && j < len(midB) && midA[i] == midB[j]) {
+			if j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]) {
+				i++
+			} else {
+				j++
+			}
+		}
+		writeHunk(prefix+startA, midA[startA:i], prefix+startB, midB[startB:j])
+		for i < len(midA) && j < len(midB) && midA[i] == midB[j] {
+			i++
+			j++
+		}
+	}
+	return out.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// hunkRange formats a 0-based start and a line count the way unified diffs
+// do: 1-based, and pointing at the line before an empty range.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	if diff := unifiedDiff("a", "b", "same\n", "same\n"); diff != "" {
+		t.Errorf("unifiedDiff() of equal texts = %q, want empty", diff)
+	}
+
+	old := "# ADRs\n\n- [One](1.md)\n- [Two](2.md)\n- [Four](4.md)\n"
+	updated := "# ADRs\n\n- [One](1.md)\n- [Three](3.md)\n- [Four](4.md)\n- [Five](5.md)\n"
+	expected := "--- a\n+++ b\n" +
+		"@@ -4 +4 @@\n-- [Two](2.md)\n+- [Three](3.md)\n" +
+		"@@ -5,0 +6 @@\n+- [Five](5.md)\n"
+	if diff := unifiedDiff("a", "b", old, updated); diff != expected {
+		t.Errorf("unifiedDiff() = %q, want %q", diff, expected)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var listItemPattern = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+)`)
+
+// wrapMarkdown hard-wraps prose lines longer than width. Headings, tables,
+// HTML, indented code and anything inside code fences are left alone. List
+// items and blockquotes keep their marker and are continued with matching
+// indentation.
+func wrapMarkdown(content string, width int) string {
+	if width <= 0 {
+		return content
+	}
+
+	var out []string
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			out = append(out, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			out = append(out, line)
+			continue
+		}
+		if len([]rune(line)) <= width || !isWrappable(line) {
+			out = append(out, line)
+			continue
+		}
+		out = append(out, wrapLine(line, width)...)
+	}
+	return strings.Join(out, "\n")
+}
+
+func isWrappable(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, "|"), strings.HasPrefix(trimmed, "<"):
+		return false
+	case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+		// Indented code, unless it is a nested list item
+		return listItemPattern.MatchString(line)
+	}
+	return true
+}
+
+func wrapLine(line string, width int) []string {
+	// Keep Markdown hard breaks on the last wrapped line
+	hardBreak := ""
+	if strings.HasSuffix(line, "  ") {
+		hardBreak = "  "
+	}
+
+	first, rest := "", line
+	if marker := listItemPattern.FindString(line); marker != "" {
+		first, rest = marker, line[len(marker):]
+	} else if trimmed := strings.TrimLeft(line, " "); strings.HasPrefix(trimmed, ">") {
+		indent := line[:len(line)-len(trimmed)]
+		first, rest = indent+"> ", strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " ")
+	} else {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		first, rest = indent, line[len(indent):]
+	}
+
+	continuation := strings.Repeat(" ", len([]rune(first)))
+	if strings.HasSuffix(strings.TrimSpace(first), ">") {
+		continuation = first
+	}
+
+	var lines []string
+	current, empty := first, true
+	for _, word := range strings.Fields(rest) {
+		if !empty && len([]rune(current))+1+len([]rune(word)) > width {
+			lines = append(lines, current)
+			current, empty = continuation, true
+		}
+		if !empty {
+			current += " "
+		}
+		current += word
+		empty = false
+	}
+	return append(lines, current+hardBreak)
+}
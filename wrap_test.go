@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestWrapMarkdown(t *testing.T) {
+	content := `# ADR 001: A heading that is far too long to fit but must never be wrapped
+
+We decided to adopt the XYZ framework for developing REST APIs in the ABC project.
+
+- Immediate or long-term benefits of the decision we made
+> We decided to adopt the XYZ framework for all new services
+
+| Option | Description that is long enough to exceed the width |
+|--------|------|
+
+` + "```" + `
+some code line that is definitely longer than the wrap width
+` + "```"
+
+	expected := `# ADR 001: A heading that is far too long to fit but must never be wrapped
+
+We decided to adopt the XYZ framework
+for developing REST APIs in the ABC
+project.
+
+- Immediate or long-term benefits of the
+  decision we made
+> We decided to adopt the XYZ framework
+> for all new services
+
+| Option | Description that is long enough to exceed the width |
+|--------|------|
+
+` + "```" + `
+some code line that is definitely longer than the wrap width
+` + "```"
+
+	if result := wrapMarkdown(content, 40); result != expected {
+		t.Errorf("wrapMarkdown() = %q, want %q", result, expected)
+	}
+	if result := wrapMarkdown(content, 0); result != content {
+		t.Error("wrapMarkdown() with width 0 should leave content unchanged")
+	}
+}